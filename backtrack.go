@@ -0,0 +1,157 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"slices"
+)
+
+// Mark is a checkpoint of the state of a [Parser] created by
+// [ParserContext.Mark]. It can be used to rewind the parser with
+// [ParserContext.Reset].
+type Mark struct {
+	id int
+}
+
+// checkpoint is the saved state of the parser for a [Mark].
+type checkpoint[V comparable] struct {
+	// id is the ID of the Mark for this checkpoint.
+	id int
+
+	root  *Node[V]
+	node  *Node[V]
+	token *Token
+	end   Position
+	index int
+
+	// states is a copy of the state stack.
+	states stack[V]
+
+	// consumed is the number of consumed tokens recorded.
+	consumed int
+
+	// undo is the number of undo functions recorded.
+	undo int
+
+	// errs is the number of errors reported.
+	errs int
+}
+
+// Mark creates a checkpoint of the current state of the parser, including the
+// token position, the current node, the shape of the parse tree, the state
+// stack, and reported errors. The parser can be rewound to the checkpoint by
+// calling [ParserContext.Reset]. This allows a [ParseState] to try parsing an
+// ambiguous construct one way and try an alternative if it fails.
+//
+// Tokens consumed and changes made to the parse tree while a mark is active
+// are recorded so they can be undone. Only changes made through the
+// [ParserContext] are recorded. Marks should be released with
+// [ParserContext.Commit] when they are no longer needed.
+func (ctx *ParserContext[V]) Mark() Mark {
+	return ctx.p.mark()
+}
+
+// Reset rewinds the parser to the state at the time m was created. Marks
+// created after m are released. m remains active and can be reset again.
+// Reset is typically called by the [ParseState] that created m. Reset panics
+// if m has already been released.
+func (ctx *ParserContext[V]) Reset(m Mark) {
+	ctx.p.reset(m)
+}
+
+// Commit releases m and any marks created after it, keeping the current
+// state of the parser. Commit panics if m has already been released.
+func (ctx *ParserContext[V]) Commit(m Mark) {
+	ctx.p.commit(m)
+}
+
+func (p *Parser[V]) mark() Mark {
+	p.markID++
+
+	p.marks = append(p.marks, checkpoint[V]{
+		id:       p.markID,
+		root:     p.root,
+		node:     p.node,
+		token:    p.token,
+		end:      p.end,
+		index:    p.index,
+		states:   slices.Clone(*p.stateStack),
+		consumed: len(p.consumed),
+		undo:     len(p.undo),
+		errs:     len(p.errs),
+	})
+
+	return Mark{id: p.markID}
+}
+
+func (p *Parser[V]) reset(m Mark) {
+	i := p.findMark(m)
+	cp := p.marks[i]
+
+	// Undo changes to the tree in reverse order.
+	for j := len(p.undo) - 1; j >= cp.undo; j-- {
+		p.undo[j]()
+	}
+
+	p.undo = p.undo[:cp.undo]
+
+	// Return consumed tokens to the lookahead buffer.
+	for j := len(p.consumed) - 1; j >= cp.consumed; j-- {
+		p.lookahead.pushFront(p.consumed[j])
+	}
+
+	p.consumed = p.consumed[:cp.consumed]
+
+	p.root = cp.root
+	p.node = cp.node
+	p.token = cp.token
+	p.end = cp.end
+	p.index = cp.index
+	*p.stateStack = slices.Clone(cp.states)
+	p.errs = p.errs[:cp.errs]
+
+	p.marks = p.marks[:i+1]
+}
+
+func (p *Parser[V]) commit(m Mark) {
+	i := p.findMark(m)
+	p.marks = p.marks[:i]
+
+	if len(p.marks) == 0 {
+		// Nothing can be rewound so the history is no longer needed.
+		p.consumed = nil
+		p.undo = nil
+	}
+}
+
+// findMark returns the index of the checkpoint for m. It panics if m is not
+// active.
+func (p *Parser[V]) findMark(m Mark) int {
+	for i := len(p.marks) - 1; i >= 0; i-- {
+		if p.marks[i].id == m.id {
+			return i
+		}
+	}
+
+	panic("lexparse: invalid or released Mark")
+}
+
+// record records a function that undoes a change to the parse tree if there
+// are any active marks.
+func (p *Parser[V]) record(undo func()) {
+	if len(p.marks) > 0 {
+		p.undo = append(p.undo, undo)
+	}
+}
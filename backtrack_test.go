@@ -0,0 +1,236 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestContext returns a new ParserContext reading words from input.
+func newTestContext(input string) *ParserContext[string] {
+	return &ParserContext[string]{
+		Context: context.Background(),
+		p:       NewParser[string](NewCustomLexer(strings.NewReader(input), &lexWordState{}), nil),
+	}
+}
+
+func TestParserContext_Reset(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext("A B C D")
+
+	_ = ctx.Next()
+	a := ctx.Push("A")
+	ctx.PushState(&parseWordState{})
+
+	wantTree := ctx.Root().Clone()
+	wantStack := len(*ctx.p.stateStack)
+
+	m := ctx.Mark()
+
+	// Try parsing B and C as children of a new node.
+	_ = ctx.Next()
+	_ = ctx.Push("B")
+	_ = ctx.Next()
+	_ = ctx.Node("C")
+	_ = ctx.Replace("B2")
+	_ = ctx.Climb()
+	_ = ctx.Climb()
+	ctx.PushState(&parseWordState{}, &parseWordState{})
+	ctx.Report(errParse)
+
+	ctx.Reset(m)
+
+	if diff := cmp.Diff(wantTree, ctx.Root()); diff != "" {
+		t.Errorf("Root (-want +got):\n%s", diff)
+	}
+
+	if ctx.Pos() != a {
+		t.Errorf("Pos: want %v, got %v", a, ctx.Pos())
+	}
+
+	if diff := cmp.Diff(wantStack, len(*ctx.p.stateStack)); diff != "" {
+		t.Errorf("state stack (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(0, len(ctx.p.errs)); diff != "" {
+		t.Errorf("errors (-want +got):\n%s", diff)
+	}
+
+	// The tokens are read again.
+	if diff := cmp.Diff("B", ctx.Next().Value); diff != "" {
+		t.Errorf("Next (-want +got):\n%s", diff)
+	}
+
+	// The mark is still active and can be reset again.
+	ctx.Reset(m)
+
+	if diff := cmp.Diff("B", ctx.Peek().Value); diff != "" {
+		t.Errorf("Peek (-want +got):\n%s", diff)
+	}
+
+	// Try the alternative.
+	_ = ctx.Next()
+	_ = ctx.Node("B")
+	ctx.Commit(m)
+
+	if diff := cmp.Diff("C", ctx.Next().Value); diff != "" {
+		t.Errorf("Next (-want +got):\n%s", diff)
+	}
+
+	var values []string
+	for _, child := range ctx.Root().Children[0].Children {
+		values = append(values, child.Value)
+	}
+
+	if diff := cmp.Diff([]string{"B"}, values); diff != "" {
+		t.Errorf("Children (-want +got):\n%s", diff)
+	}
+
+	if len(ctx.p.consumed) != 0 || len(ctx.p.undo) != 0 {
+		t.Errorf("history not released: %d tokens, %d undo", len(ctx.p.consumed), len(ctx.p.undo))
+	}
+}
+
+func TestParserContext_Reset_root(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext("A B")
+
+	_ = ctx.Next()
+	_ = ctx.Node("A")
+
+	root := ctx.Root()
+	wantTree := root.Clone()
+
+	m := ctx.Mark()
+
+	_ = ctx.Next()
+	_ = ctx.Replace("new root")
+	ctx.SetRoot(ctx.NewNode("other root"))
+
+	ctx.Reset(m)
+
+	if ctx.Root() != root {
+		t.Errorf("Root: want %v, got %v", root, ctx.Root())
+	}
+
+	if diff := cmp.Diff(wantTree, ctx.Root()); diff != "" {
+		t.Errorf("Root (-want +got):\n%s", diff)
+	}
+}
+
+func TestParserContext_Reset_nested(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext("A B C D")
+
+	m1 := ctx.Mark()
+	_ = ctx.Next()
+
+	m2 := ctx.Mark()
+	_ = ctx.Next()
+
+	ctx.Reset(m2)
+
+	if diff := cmp.Diff("B", ctx.Peek().Value); diff != "" {
+		t.Errorf("Peek (-want +got):\n%s", diff)
+	}
+
+	_ = ctx.Next()
+	_ = ctx.Next()
+
+	ctx.Reset(m1)
+
+	if diff := cmp.Diff("A", ctx.Peek().Value); diff != "" {
+		t.Errorf("Peek (-want +got):\n%s", diff)
+	}
+
+	// m2 was released by resetting m1.
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected panic for released mark")
+			}
+		}()
+
+		ctx.Reset(m2)
+	}()
+
+	ctx.Commit(m1)
+
+	// m1 was released by Commit.
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected panic for released mark")
+			}
+		}()
+
+		ctx.Commit(m1)
+	}()
+}
+
+func TestParserContext_Mark_parse(t *testing.T) {
+	t.Parallel()
+
+	// Parse "( word )" as a group if possible and otherwise parse the tokens as
+	// individual words.
+	var parseGroup ParseState[string]
+
+	parseGroup = ParseStateFn(func(ctx *ParserContext[string]) error {
+		if ctx.Peek().Type == TokenTypeEOF {
+			return nil
+		}
+
+		ctx.PushState(parseGroup)
+
+		m := ctx.Mark()
+		defer ctx.Commit(m)
+
+		if ctx.Next().Value == "(" {
+			word := ctx.Next()
+			if ctx.Next().Value == ")" {
+				ctx.Node("group " + word.Value)
+				return nil
+			}
+		}
+
+		ctx.Reset(m)
+		ctx.Node(ctx.Next().Value)
+
+		return nil
+	})
+
+	l := NewCustomLexer(strings.NewReader("( a ) ( b c )"), &lexWordState{})
+
+	root, err := NewParser(l, parseGroup).Parse(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var values []string
+	for _, child := range root.Children {
+		values = append(values, child.Value)
+	}
+
+	if diff := cmp.Diff([]string{"group a", "(", "b", "c", ")"}, values); diff != "" {
+		t.Errorf("Children (-want +got):\n%s", diff)
+	}
+}
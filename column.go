@@ -0,0 +1,142 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"unicode"
+	"unicode/utf16"
+)
+
+// ColumnMode determines how the Column of a [Position] is counted.
+type ColumnMode int
+
+const (
+	// ColumnRunes counts columns in Unicode code points. This is the default.
+	ColumnRunes ColumnMode = iota
+
+	// ColumnBytes counts columns in bytes of the UTF-8 encoded input.
+	ColumnBytes
+
+	// ColumnUTF16 counts columns in UTF-16 code units. This is the unit used
+	// by many editors and the Language Server Protocol.
+	ColumnUTF16
+
+	// ColumnGraphemes counts columns in user-perceived characters. Combining
+	// marks, emoji modifiers, regional indicator pairs, and characters joined
+	// by a zero width joiner are counted together with the preceding
+	// character. This is an approximation of Unicode extended grapheme
+	// clusters.
+	ColumnGraphemes
+)
+
+const (
+	// zeroWidthJoiner joins adjacent characters into a single grapheme.
+	zeroWidthJoiner = '\u200d'
+
+	// emojiModifierFirst and emojiModifierLast are the bounds of the emoji
+	// skin tone modifiers.
+	emojiModifierFirst = '\U0001f3fb'
+	emojiModifierLast  = '\U0001f3ff'
+)
+
+// columnCounter advances positions, counting columns according to a
+// [ColumnMode].
+type columnCounter struct {
+	// mode is the unit that columns are counted in.
+	mode ColumnMode
+
+	// tabWidth is the distance between tab stops. If tabWidth is zero or
+	// less, a tab is counted like any other character.
+	tabWidth int
+
+	// prev is the previous rune on the current line.
+	prev rune
+
+	// regional is the number of consecutive regional indicators preceding the
+	// current position.
+	regional int
+}
+
+// advance updates pos after reading the rune rn which was encoded in the
+// input as size bytes. Invalid UTF-8 bytes are read as [utf8.RuneError] with a
+// size of 1 so size must be taken from the input rather than computed from rn.
+func (c *columnCounter) advance(pos *Position, rn rune, size int) {
+	pos.Offset += size
+
+	if rn == '\n' {
+		pos.Line++
+		pos.Column = 1
+		c.prev = 0
+		c.regional = 0
+
+		return
+	}
+
+	pos.Column = c.next(pos.Column, rn, size)
+	c.prev = rn
+}
+
+// next returns the column following rn given that rn was read at column col.
+func (c *columnCounter) next(col int, rn rune, size int) int {
+	if rn == '\t' && c.tabWidth > 0 {
+		return ((col-1)/c.tabWidth+1)*c.tabWidth + 1
+	}
+
+	switch c.mode {
+	case ColumnBytes:
+		return col + size
+	case ColumnUTF16:
+		if n := utf16.RuneLen(rn); n > 0 {
+			return col + n
+		}
+
+		return col + 1
+	case ColumnGraphemes:
+		if c.extendsGrapheme(rn) {
+			return col
+		}
+
+		return col + 1
+	default:
+		return col + 1
+	}
+}
+
+// extendsGrapheme returns true if rn continues the grapheme cluster started by
+// the previous rune on the line.
+func (c *columnCounter) extendsGrapheme(rn rune) bool {
+	if unicode.Is(unicode.Regional_Indicator, rn) {
+		c.regional++
+		// Regional indicators are paired to form flags.
+		return c.regional%2 == 0
+	}
+
+	c.regional = 0
+
+	if c.prev == 0 {
+		// Nothing to extend at the start of a line.
+		return false
+	}
+
+	if c.prev == zeroWidthJoiner || rn == zeroWidthJoiner {
+		return true
+	}
+
+	if rn >= emojiModifierFirst && rn <= emojiModifierLast {
+		return true
+	}
+
+	return unicode.In(rn, unicode.Mn, unicode.Me, unicode.Mc)
+}
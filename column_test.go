@@ -0,0 +1,297 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// findToken returns the first token from l with the given value.
+func findToken(t *testing.T, l Lexer, value string) *Token {
+	t.Helper()
+
+	for {
+		token := l.NextToken(context.Background())
+		if token.Value == value {
+			return token
+		}
+
+		if token.Type == TokenTypeEOF {
+			t.Fatalf("token %q not found", value)
+		}
+	}
+}
+
+func TestColumnMode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		mode     ColumnMode
+		tabWidth int
+
+		// expected is the expected position of the token "x".
+		expected Position
+	}{
+		{
+			name:  "runes multi-byte",
+			input: "日本 x",
+			mode:  ColumnRunes,
+			expected: Position{
+				Offset: 7,
+				Line:   1,
+				Column: 4,
+			},
+		},
+		{
+			name:  "bytes multi-byte",
+			input: "日本 x",
+			mode:  ColumnBytes,
+			expected: Position{
+				Offset: 7,
+				Line:   1,
+				Column: 8,
+			},
+		},
+		{
+			name:  "utf-16 multi-byte",
+			input: "日本 x",
+			mode:  ColumnUTF16,
+			expected: Position{
+				Offset: 7,
+				Line:   1,
+				Column: 4,
+			},
+		},
+		{
+			name:  "utf-16 surrogate pair",
+			input: "\U0001f600 x",
+			mode:  ColumnUTF16,
+			expected: Position{
+				Offset: 5,
+				Line:   1,
+				Column: 4,
+			},
+		},
+		{
+			name:  "runes combining characters",
+			input: "e\u0301 x",
+			mode:  ColumnRunes,
+			expected: Position{
+				Offset: 4,
+				Line:   1,
+				Column: 4,
+			},
+		},
+		{
+			name:  "graphemes combining characters",
+			input: "e\u0301 x",
+			mode:  ColumnGraphemes,
+			expected: Position{
+				Offset: 4,
+				Line:   1,
+				Column: 3,
+			},
+		},
+		{
+			name:  "graphemes regional indicators",
+			input: "\U0001f1ef\U0001f1f5\U0001f1ef\U0001f1f5 x",
+			mode:  ColumnGraphemes,
+			expected: Position{
+				Offset: 17,
+				Line:   1,
+				Column: 4,
+			},
+		},
+		{
+			name:  "graphemes zero width joiner",
+			input: "\U0001f468\u200d\U0001f469\u200d\U0001f467 x",
+			mode:  ColumnGraphemes,
+			expected: Position{
+				Offset: 19,
+				Line:   1,
+				Column: 3,
+			},
+		},
+		{
+			name:  "graphemes new line",
+			input: "e\u0301\ne\u0301 x",
+			mode:  ColumnGraphemes,
+			expected: Position{
+				Offset: 8,
+				Line:   2,
+				Column: 3,
+			},
+		},
+		{
+			name:     "tab stop",
+			input:    "ab\tx",
+			mode:     ColumnRunes,
+			tabWidth: 4,
+			expected: Position{
+				Offset: 3,
+				Line:   1,
+				Column: 5,
+			},
+		},
+		{
+			name:     "tab at tab stop",
+			input:    "abcd\tx",
+			mode:     ColumnRunes,
+			tabWidth: 4,
+			expected: Position{
+				Offset: 5,
+				Line:   1,
+				Column: 9,
+			},
+		},
+		{
+			name:     "tab stop bytes",
+			input:    "日\tx",
+			mode:     ColumnBytes,
+			tabWidth: 8,
+			expected: Position{
+				Offset: 4,
+				Line:   1,
+				Column: 9,
+			},
+		},
+		{
+			name:  "no tab width",
+			input: "\ta\tx",
+			mode:  ColumnRunes,
+			expected: Position{
+				Offset: 3,
+				Line:   1,
+				Column: 4,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("CustomLexer", func(t *testing.T) {
+				t.Parallel()
+
+				l := NewCustomLexer(strings.NewReader(tc.input), &lexWordState{})
+				l.SetColumnMode(tc.mode)
+				l.SetTabWidth(tc.tabWidth)
+
+				if diff := cmp.Diff(tc.expected, findToken(t, l, "x").Start); diff != "" {
+					t.Errorf("Start (-want +got):\n%s", diff)
+				}
+			})
+
+			t.Run("ScanningLexer", func(t *testing.T) {
+				t.Parallel()
+
+				l := NewScanningLexer(strings.NewReader(tc.input))
+				l.SetColumnMode(tc.mode)
+				l.SetTabWidth(tc.tabWidth)
+
+				if diff := cmp.Diff(tc.expected, findToken(t, l, "x").Start); diff != "" {
+					t.Errorf("Start (-want +got):\n%s", diff)
+				}
+			})
+		})
+	}
+}
+
+func TestColumnMode_errorTokens(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		mode     ColumnMode
+		tabWidth int
+
+		// expected is the expected start position of the error token.
+		expected Position
+	}{
+		{
+			name:     "tab stop",
+			input:    "\t\t\"abc",
+			mode:     ColumnRunes,
+			tabWidth: 8,
+			expected: Position{
+				Offset: 2,
+				Line:   1,
+				Column: 17,
+			},
+		},
+		{
+			name:  "bytes multi-byte",
+			input: "日本 'ab",
+			mode:  ColumnBytes,
+			expected: Position{
+				Offset: 7,
+				Line:   1,
+				Column: 8,
+			},
+		},
+		{
+			name:  "graphemes after skipped comment",
+			input: "e\u0301 /* a */ \"abc",
+			mode:  ColumnGraphemes,
+			expected: Position{
+				Offset: 12,
+				Line:   1,
+				Column: 11,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := NewScanningLexer(strings.NewReader(tc.input), WithSkipComments())
+			l.SetColumnMode(tc.mode)
+			l.SetTabWidth(tc.tabWidth)
+			l.SetErrorTokens(true)
+
+			token := l.NextToken(context.Background())
+			for token.Type != TokenTypeError && token.Type != TokenTypeEOF {
+				token = l.NextToken(context.Background())
+			}
+
+			if diff := cmp.Diff(TokenTypeError, token.Type); diff != "" {
+				t.Fatalf("Type (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expected, token.Start); diff != "" {
+				t.Errorf("Start (-want +got):\n%s", diff)
+			}
+
+			var lexErr *Error
+			if !errors.As(token.Err, &lexErr) {
+				t.Fatalf("Err: want *Error, got %T", token.Err)
+			}
+
+			if diff := cmp.Diff(tc.expected, lexErr.Span.Start); diff != "" {
+				t.Errorf("Err Span.Start (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -111,6 +111,16 @@ func (ctx *CustomLexerContext) Emit(typ TokenType) *Token {
 	return ctx.l.emit(typ)
 }
 
+// EmitError emits a token of type [TokenTypeError] between the current cursor
+// position and reader position and returns the token. The token's Err field
+// is set to err as an [*Error] spanning the token. Unlike returning an error
+// from [LexState.Run], lexing continues with the next state so that the
+// parser can handle the error token and continue parsing. This advances the
+// current token cursor.
+func (ctx *CustomLexerContext) EmitError(err error) *Token {
+	return ctx.l.emitError(err)
+}
+
 // Find searches the input for one of the given search strings, advancing the
 // reader, and stopping when one of the strings is found. The token cursor is
 // not advanced. The string found is returned. If no match is found an empty
@@ -159,8 +169,8 @@ func (ctx *CustomLexerContext) Token() string {
 	return ctx.l.b.String()
 }
 
-// Width returns the current width of the token being processed. It is
-// equivalent to l.Pos().Offset - l.Cursor().Offset.
+// Width returns the current width in bytes of the token being processed. It
+// is equivalent to l.Pos().Offset - l.Cursor().Offset.
 func (ctx *CustomLexerContext) Width() int {
 	return ctx.l.pos.Offset - ctx.l.cursor.Offset
 }
@@ -181,6 +191,9 @@ type CustomLexer struct {
 	// r is the underlying reader to read from.
 	r *runeio.RuneReader
 
+	// sizes records the encoded size of the runes read by r.
+	sizes *runeSizeReader
+
 	// b is a strings builder that stores the current token value.
 	b strings.Builder
 
@@ -190,6 +203,9 @@ type CustomLexer struct {
 	// cursor is the start position of the current token.
 	cursor Position
 
+	// cols counts the columns of pos.
+	cols columnCounter
+
 	// err is the first error the lexer encountered.
 	err error
 }
@@ -227,11 +243,47 @@ func NewCustomLexer(reader io.Reader, startingState LexState) *CustomLexer {
 		br = bufio.NewReader(reader)
 	}
 
-	customLexer.r = runeio.NewReader(br)
+	customLexer.sizes = &runeSizeReader{r: br}
+	customLexer.r = runeio.NewReader(customLexer.sizes)
 
 	return customLexer
 }
 
+// runeSizeReader is an [io.RuneReader] that records the size in bytes of each
+// rune read from the underlying reader. The [runeio.RuneReader] only reports
+// the size of runes as re-encoded, which differs from the input for invalid
+// UTF-8 bytes.
+type runeSizeReader struct {
+	r     io.RuneReader
+	sizes []int
+}
+
+// ReadRune implements [io.RuneReader.ReadRune].
+func (r *runeSizeReader) ReadRune() (rune, int, error) {
+	rn, size, err := r.r.ReadRune()
+	if err == nil {
+		r.sizes = append(r.sizes, size)
+	}
+
+	//nolint:wrapcheck // errors must be returned unwrapped to the rune reader.
+	return rn, size, err
+}
+
+// next returns the size of the next rune in the input that has not yet been
+// consumed by the lexer.
+func (r *runeSizeReader) next() int {
+	if len(r.sizes) == 0 {
+		// NOTE: This should not happen since runes are read from the
+		// underlying reader before they are consumed.
+		return 1
+	}
+
+	size := r.sizes[0]
+	r.sizes = r.sizes[1:]
+
+	return size
+}
+
 // NextToken implements [Lexer.NextToken] and returns the next token from the
 // input stream. If the end of the input is reached, a token with type
 // [TokenTypeEOF] is returned.
@@ -292,13 +344,7 @@ func (l *CustomLexer) nextRune() rune {
 		return EOF
 	}
 
-	l.pos.Offset++
-
-	l.pos.Column++
-	if rn == '\n' {
-		l.pos.Line++
-		l.pos.Column = 1
-	}
+	l.updatePos(rn)
 
 	_, _ = l.b.WriteRune(rn)
 
@@ -345,19 +391,13 @@ func (l *CustomLexer) advance(numRunes int, discard bool) int {
 		// Advance by peeked amount.
 		numDiscarded, dErr := l.r.Discard(len(peekedRunes))
 		advanced += numDiscarded
-		l.pos.Offset += numDiscarded
 
 		// NOTE: We must be careful since toRead could be different from # of
 		// runes peeked and/or discarded. We will only actually advance by the
 		// number of runes discarded in the underlying reader to maintain
 		// consistency.
 		for i := range numDiscarded {
-			if peekedRunes[i] == '\n' {
-				l.pos.Line++
-				l.pos.Column = 1
-			} else {
-				l.pos.Column++
-			}
+			l.updatePos(peekedRunes[i])
 		}
 
 		if !discard {
@@ -441,6 +481,20 @@ func (l *CustomLexer) emit(typ TokenType) *Token {
 	return token
 }
 
+func (l *CustomLexer) emitError(err error) *Token {
+	if l.err != nil {
+		return nil
+	}
+
+	token := l.newToken(TokenTypeError)
+	token.Err = wrapError(token.Span(), err)
+
+	l.buf = append(l.buf, token)
+	l.ignore()
+
+	return token
+}
+
 func (l *CustomLexer) find(query []string) string {
 	var maxLen int
 	for i := range query {
@@ -472,6 +526,12 @@ func (l *CustomLexer) find(query []string) string {
 	}
 }
 
+// updatePos updates the reader position after reading the rune rn. The
+// offset is advanced by the number of bytes rn was encoded as in the input.
+func (l *CustomLexer) updatePos(rn rune) {
+	l.cols.advance(&l.pos, rn, l.sizes.next())
+}
+
 func (l *CustomLexer) ignore() {
 	l.cursor = l.pos
 	l.b.Reset()
@@ -499,15 +559,22 @@ func (l *CustomLexer) peekN(n int) []rune {
 	return p
 }
 
-// Err returns any errors that the lexer encountered.
+// Err returns any errors that the lexer encountered. Errors are returned as
+// an [*Error] spanning the input consumed for the token being lexed when the
+// error occurred, unless the error was caused by the context being done.
 func (l *CustomLexer) Err() error {
 	return l.err
 }
 
 func (l *CustomLexer) setErr(err error) {
-	if l.err == nil && !errors.Is(err, io.EOF) {
-		l.err = err
+	if l.err != nil || err == nil || errors.Is(err, io.EOF) {
+		return
 	}
+
+	l.err = wrapError(Span{
+		Start: l.cursor,
+		End:   l.pos,
+	}, err)
 }
 
 // SetFilename sets the filename in the lexer's positional information.
@@ -515,3 +582,17 @@ func (l *CustomLexer) SetFilename(name string) {
 	l.pos.Filename = name
 	l.cursor.Filename = name
 }
+
+// SetColumnMode sets how the lexer counts the Column of positions. It should
+// be called before lexing begins.
+func (l *CustomLexer) SetColumnMode(mode ColumnMode) {
+	l.cols.mode = mode
+}
+
+// SetTabWidth sets the distance between tab stops used when counting columns.
+// A tab advances the column to the next tab stop. If width is zero, the
+// default, a tab is counted like any other character. It should be called
+// before lexing begins.
+func (l *CustomLexer) SetTabWidth(width int) {
+	l.cols.tabWidth = width
+}
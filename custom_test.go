@@ -968,3 +968,327 @@ func TestCustomLexer_SetFilename(t *testing.T) {
 		}
 	})
 }
+
+func TestCustomLexerContext_utf8(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		input string
+
+		// run performs the operation under test and returns the string result,
+		// if any.
+		run func(ctx *CustomLexerContext) string
+
+		expectedResult string
+		expectedPos    Position
+		expectedCursor Position
+		expectedToken  string
+
+		// expectedWidth is the expected token width in bytes if it differs
+		// from the length of expectedToken. Invalid UTF-8 bytes are one byte
+		// in the input but are decoded as utf8.RuneError.
+		expectedWidth int
+	}{
+		{
+			name:  "advance multi-byte",
+			input: "日本語",
+			run: func(ctx *CustomLexerContext) string {
+				_ = ctx.AdvanceN(2)
+				return ""
+			},
+			expectedPos: Position{
+				Offset: 6,
+				Line:   1,
+				Column: 3,
+			},
+			expectedCursor: Position{
+				Offset: 0,
+				Line:   1,
+				Column: 1,
+			},
+			expectedToken: "日本",
+		},
+		{
+			name:  "advance crlf",
+			input: "a\r\nb",
+			run: func(ctx *CustomLexerContext) string {
+				_ = ctx.AdvanceN(3)
+				return ""
+			},
+			expectedPos: Position{
+				Offset: 3,
+				Line:   2,
+				Column: 1,
+			},
+			expectedCursor: Position{
+				Offset: 0,
+				Line:   1,
+				Column: 1,
+			},
+			expectedToken: "a\r\n",
+		},
+		{
+			name:  "next rune multi-byte",
+			input: "ü!",
+			run: func(ctx *CustomLexerContext) string {
+				return string(ctx.NextRune())
+			},
+			expectedResult: "ü",
+			expectedPos: Position{
+				Offset: 2,
+				Line:   1,
+				Column: 2,
+			},
+			expectedCursor: Position{
+				Offset: 0,
+				Line:   1,
+				Column: 1,
+			},
+			expectedToken: "ü",
+		},
+		{
+			name:  "discard multi-byte",
+			input: "日本語",
+			run: func(ctx *CustomLexerContext) string {
+				_ = ctx.Discard()
+				return ""
+			},
+			expectedPos: Position{
+				Offset: 3,
+				Line:   1,
+				Column: 2,
+			},
+			expectedCursor: Position{
+				Offset: 3,
+				Line:   1,
+				Column: 2,
+			},
+		},
+		{
+			name:  "discard combining characters",
+			input: "e\u0301x",
+			run: func(ctx *CustomLexerContext) string {
+				_ = ctx.DiscardN(2)
+				return ""
+			},
+			expectedPos: Position{
+				Offset: 3,
+				Line:   1,
+				Column: 3,
+			},
+			expectedCursor: Position{
+				Offset: 3,
+				Line:   1,
+				Column: 3,
+			},
+		},
+		{
+			name:  "find multi-byte",
+			input: "こんにちは=世界",
+			run: func(ctx *CustomLexerContext) string {
+				return ctx.Find([]string{"="})
+			},
+			expectedResult: "=",
+			expectedPos: Position{
+				Offset: 15,
+				Line:   1,
+				Column: 6,
+			},
+			expectedCursor: Position{
+				Offset: 0,
+				Line:   1,
+				Column: 1,
+			},
+			expectedToken: "こんにちは",
+		},
+		{
+			name:  "find multi-byte query",
+			input: "a\r\nb世界",
+			run: func(ctx *CustomLexerContext) string {
+				return ctx.Find([]string{"世界"})
+			},
+			expectedResult: "世界",
+			expectedPos: Position{
+				Offset: 4,
+				Line:   2,
+				Column: 2,
+			},
+			expectedCursor: Position{
+				Offset: 0,
+				Line:   1,
+				Column: 1,
+			},
+			expectedToken: "a\r\nb",
+		},
+		{
+			name:  "discard to crlf multi-byte",
+			input: "\u00e9\r\n日本=x",
+			run: func(ctx *CustomLexerContext) string {
+				return ctx.DiscardTo([]string{"="})
+			},
+			expectedResult: "=",
+			expectedPos: Position{
+				Offset: 10,
+				Line:   2,
+				Column: 3,
+			},
+			expectedCursor: Position{
+				Offset: 10,
+				Line:   2,
+				Column: 3,
+			},
+		},
+		{
+			name:  "discard to combining characters",
+			input: "e\u0301e\u0301|",
+			run: func(ctx *CustomLexerContext) string {
+				return ctx.DiscardTo([]string{"|"})
+			},
+			expectedResult: "|",
+			expectedPos: Position{
+				Offset: 6,
+				Line:   1,
+				Column: 5,
+			},
+			expectedCursor: Position{
+				Offset: 6,
+				Line:   1,
+				Column: 5,
+			},
+		},
+		{
+			name:  "advance invalid byte",
+			input: "a\xffb",
+			run: func(ctx *CustomLexerContext) string {
+				_ = ctx.AdvanceN(2)
+				return ""
+			},
+			expectedPos: Position{
+				Offset: 2,
+				Line:   1,
+				Column: 3,
+			},
+			expectedCursor: Position{
+				Offset: 0,
+				Line:   1,
+				Column: 1,
+			},
+			expectedToken: "a\uFFFD",
+			expectedWidth: 2,
+		},
+		{
+			name:  "next rune invalid byte",
+			input: "\xffb",
+			run: func(ctx *CustomLexerContext) string {
+				return string(ctx.NextRune())
+			},
+			expectedResult: "\uFFFD",
+			expectedPos: Position{
+				Offset: 1,
+				Line:   1,
+				Column: 2,
+			},
+			expectedCursor: Position{
+				Offset: 0,
+				Line:   1,
+				Column: 1,
+			},
+			expectedToken: "\uFFFD",
+			expectedWidth: 1,
+		},
+		{
+			name:  "discard invalid byte",
+			input: "a\xffb",
+			run: func(ctx *CustomLexerContext) string {
+				_ = ctx.DiscardN(2)
+				return string(ctx.NextRune())
+			},
+			expectedResult: "b",
+			expectedPos: Position{
+				Offset: 3,
+				Line:   1,
+				Column: 4,
+			},
+			expectedCursor: Position{
+				Offset: 2,
+				Line:   1,
+				Column: 3,
+			},
+			expectedToken: "b",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := CustomLexerContext{
+				Context: context.Background(),
+				l:       NewCustomLexer(strings.NewReader(tc.input), &lexWordState{}),
+			}
+
+			if diff := cmp.Diff(tc.expectedResult, tc.run(&ctx)); diff != "" {
+				t.Errorf("result (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expectedPos, ctx.Pos()); diff != "" {
+				t.Errorf("Pos (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expectedCursor, ctx.Cursor()); diff != "" {
+				t.Errorf("Cursor (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expectedToken, ctx.Token()); diff != "" {
+				t.Errorf("Token (-want +got):\n%s", diff)
+			}
+
+			// The token width is measured in bytes.
+			expectedWidth := len(tc.expectedToken)
+			if tc.expectedWidth != 0 {
+				expectedWidth = tc.expectedWidth
+			}
+
+			if diff := cmp.Diff(expectedWidth, ctx.Width()); diff != "" {
+				t.Errorf("Width (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(nil, ctx.l.Err(), cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Err (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCustomLexer_NextToken_offsets(t *testing.T) {
+	t.Parallel()
+
+	input := "héllo wörld\r\n日本語 e\u0301"
+
+	customLexer := NewCustomLexer(strings.NewReader(input), &lexWordState{})
+
+	var values []string
+
+	for {
+		token := customLexer.NextToken(context.Background())
+		if token.Type == TokenTypeEOF {
+			break
+		}
+
+		// Offsets can be used to slice the original input.
+		if diff := cmp.Diff(token.Value, input[token.Start.Offset:token.End.Offset]); diff != "" {
+			t.Errorf("input[%d:%d] (-want +got):\n%s", token.Start.Offset, token.End.Offset, diff)
+		}
+
+		values = append(values, token.Value)
+	}
+
+	if diff := cmp.Diff([]string{"héllo", "wörld", "\n日本語", "e\u0301"}, values); diff != "" {
+		t.Errorf("values (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(nil, customLexer.Err(), cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Err (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnexpectedToken is the cause of errors for tokens found where they are not
+// expected.
+var ErrUnexpectedToken = errors.New("unexpected token")
+
+// Severity is the severity of an [Error].
+type Severity int
+
+const (
+	// SeverityError indicates a problem that prevents the input from being
+	// processed. This is the default.
+	SeverityError Severity = iota
+
+	// SeverityWarning indicates a problem that does not prevent the input
+	// from being processed.
+	SeverityWarning
+
+	// SeverityNote indicates an informational message.
+	SeverityNote
+)
+
+// String returns a string representation of the Severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityNote:
+		return "note"
+	default:
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// Error is an error found at a location in the input. Errors returned by
+// [CustomLexer], [ScanningLexer], and [Parser] are of this type unless they
+// are caused by the [context.Context] being done. Errors can be printed with
+// an excerpt of the input using [RenderError].
+type Error struct {
+	// Severity is the severity of the error.
+	Severity Severity
+
+	// Span is the span of the input where the error was found.
+	Span Span
+
+	// Message is an optional message describing the error in addition to
+	// Err.
+	Message string
+
+	// Err is the underlying cause of the error. It can be nil if Message is
+	// set.
+	Err error
+
+	// Notes are optional additional notes about the error.
+	Notes []string
+}
+
+// NewError creates a new [Error] for the given span of the input caused by
+// err.
+func NewError(span Span, err error) *Error {
+	return &Error{
+		Severity: SeverityError,
+		Span:     span,
+		Err:      err,
+	}
+}
+
+// NewTokenError creates a new [Error] at the span of the given token caused
+// by err. The token's value is used as the error message.
+func NewTokenError(token *Token, err error) *Error {
+	value := strconv.Quote(token.Value)
+	if token.Type == TokenTypeEOF {
+		value = "<EOF>"
+	}
+
+	e := NewError(token.Span(), err)
+	e.Message = value
+
+	return e
+}
+
+// Error implements the error interface. The error is formatted as the start
+// position of the span followed by the cause and message.
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	if e.Span.Start.Line > 0 {
+		b.WriteString(e.Span.Start.String())
+		b.WriteString(": ")
+	}
+
+	if e.Severity != SeverityError {
+		b.WriteString(e.Severity.String())
+		b.WriteString(": ")
+	}
+
+	b.WriteString(e.message())
+
+	return b.String()
+}
+
+// Unwrap returns the underlying cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// message returns the cause and message of the error.
+func (e *Error) message() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Err.Error() + ": " + e.Message
+	}
+}
+
+// ErrorList is a list of errors. It is returned by [Parser.Parse] when errors
+// are reported with [ParserContext.Report].
+type ErrorList []error
+
+// Error implements the error interface. Each error is printed on a separate
+// line.
+func (l ErrorList) Error() string {
+	msgs := make([]string, 0, len(l))
+	for _, err := range l {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors in the list.
+func (l ErrorList) Unwrap() []error {
+	return l
+}
+
+// wrapError returns err as an [*Error] at the given span. Errors caused by a
+// [context.Context] being done and errors that already contain an [*Error]
+// are returned unchanged.
+func wrapError(span Span, err error) error {
+	var lpErr *Error
+	if errors.As(err, &lpErr) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	return NewError(span, err)
+}
+
+// RenderError writes err to w in a human readable format. If err is or wraps
+// an [*Error], an excerpt of the line in src where the error was found is
+// printed with the span of the error underlined. For example:
+//
+//	error: unexpected identifier: "foo"
+//	 --> example.txt:1:5
+//	  |
+//	1 | bar foo
+//	  |     ^^^
+//
+// Errors that wrap multiple errors, such as those created by [errors.Join],
+// are rendered one after the other. src can be nil in which case no excerpt
+// is printed.
+func RenderError(w io.Writer, src *SourceFile, err error) error {
+	var b strings.Builder
+
+	renderError(&b, src, err)
+
+	if _, wErr := io.WriteString(w, b.String()); wErr != nil {
+		return fmt.Errorf("writing error: %w", wErr)
+	}
+
+	return nil
+}
+
+func renderError(b *strings.Builder, src *SourceFile, err error) {
+	if err == nil {
+		return
+	}
+
+	//nolint:errorlint // Only errors that directly wrap multiple errors are rendered separately.
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range multi.Unwrap() {
+			renderError(b, src, e)
+		}
+
+		return
+	}
+
+	var lpErr *Error
+	if !errors.As(err, &lpErr) {
+		b.WriteString(SeverityError.String())
+		b.WriteString(": ")
+		b.WriteString(err.Error())
+		b.WriteString("\n")
+
+		return
+	}
+
+	b.WriteString(lpErr.Severity.String())
+	b.WriteString(": ")
+	b.WriteString(lpErr.message())
+	b.WriteString("\n")
+
+	start := lpErr.Span.Start
+	if start.Line == 0 {
+		// The error has no position information.
+		renderNotes(b, "", lpErr.Notes)
+		return
+	}
+
+	lineNum := strconv.Itoa(start.Line)
+	gutter := strings.Repeat(" ", len(lineNum))
+
+	b.WriteString(gutter)
+	b.WriteString("--> ")
+	b.WriteString(start.String())
+	b.WriteString("\n")
+
+	if src != nil && start.Offset >= 0 && start.Offset <= len(src.src) {
+		n := src.lineAt(start.Offset)
+		lineNum = strconv.Itoa(n)
+		gutter = strings.Repeat(" ", len(lineNum))
+		line := src.Line(n)
+		lineStart := src.lines[n-1]
+
+		b.WriteString(gutter)
+		b.WriteString(" |\n")
+		b.WriteString(lineNum)
+		b.WriteString(" | ")
+		b.WriteString(line)
+		b.WriteString("\n")
+		b.WriteString(gutter)
+		b.WriteString(" | ")
+
+		// Pad up to the start of the span, keeping tabs so that the carets
+		// line up with the text above.
+		for _, rn := range line[:min(start.Offset-lineStart, len(line))] {
+			if rn == '\t' {
+				b.WriteRune('\t')
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+
+		// Underline the span up to the end of the line.
+		end := min(lpErr.Span.End.Offset, lineStart+len(line))
+		width := utf8.RuneCount(src.src[start.Offset:max(end, start.Offset)])
+		b.WriteString(strings.Repeat("^", max(width, 1)))
+		b.WriteString("\n")
+	}
+
+	renderNotes(b, gutter, lpErr.Notes)
+}
+
+func renderNotes(b *strings.Builder, gutter string, notes []string) {
+	for _, note := range notes {
+		b.WriteString(gutter)
+		b.WriteString(" = ")
+		b.WriteString(SeverityNote.String())
+		b.WriteString(": ")
+		b.WriteString(note)
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,454 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestError_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		err      *Error
+		expected string
+	}{
+		{
+			name:     "cause",
+			err:      NewError(span(4, 7), errState),
+			expected: "1:5: errState",
+		},
+		{
+			name: "cause and message",
+			err: &Error{
+				Span:    span(4, 7),
+				Err:     errState,
+				Message: "foo",
+			},
+			expected: "1:5: errState: foo",
+		},
+		{
+			name: "message",
+			err: &Error{
+				Span:    span(4, 7),
+				Message: "foo",
+			},
+			expected: "1:5: foo",
+		},
+		{
+			name: "warning",
+			err: &Error{
+				Severity: SeverityWarning,
+				Span:     span(4, 7),
+				Message:  "foo",
+			},
+			expected: "1:5: warning: foo",
+		},
+		{
+			name:     "no position",
+			err:      NewError(Span{}, errState),
+			expected: "errState",
+		},
+		{
+			name: "token",
+			err: NewTokenError(&Token{
+				Value: "foo",
+				Start: span(4, 7).Start,
+				End:   span(4, 7).End,
+			}, errParse),
+			expected: `1:5: errParse: "foo"`,
+		},
+		{
+			name: "EOF token",
+			err: NewTokenError(&Token{
+				Type:  TokenTypeEOF,
+				Start: span(4, 4).Start,
+				End:   span(4, 4).End,
+			}, errParse),
+			expected: "1:5: errParse: <EOF>",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, tc.err.Error()); diff != "" {
+				t.Errorf("Error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	t.Parallel()
+
+	src := NewSourceFile("test.txt", []byte("first line\n\tbär foo = 1\nlast"))
+
+	testCases := []struct {
+		name     string
+		src      *SourceFile
+		err      error
+		expected string
+	}{
+		{
+			name: "basic",
+			src:  src,
+			err: &Error{
+				Span: Span{
+					Start: Position{Filename: "test.txt", Offset: 17, Line: 2, Column: 6},
+					End:   Position{Filename: "test.txt", Offset: 20, Line: 2, Column: 9},
+				},
+				Err:     errParse,
+				Message: `"foo"`,
+				Notes:   []string{"foo is not allowed here"},
+			},
+			expected: "" +
+				"error: errParse: \"foo\"\n" +
+				" --> test.txt:2:6\n" +
+				"  |\n" +
+				"2 | \tbär foo = 1\n" +
+				"  | \t    ^^^\n" +
+				"  = note: foo is not allowed here\n",
+		},
+		{
+			name: "multi-line span",
+			src:  src,
+			err: NewError(Span{
+				Start: Position{Offset: 6, Line: 1, Column: 7},
+				End:   Position{Offset: 27, Line: 3, Column: 2},
+			}, errParse),
+			expected: "" +
+				"error: errParse\n" +
+				" --> 1:7\n" +
+				"  |\n" +
+				"1 | first line\n" +
+				"  |       ^^^^\n",
+		},
+		{
+			name: "empty span",
+			src:  src,
+			err: NewError(Span{
+				Start: Position{Offset: 29, Line: 3, Column: 5},
+				End:   Position{Offset: 29, Line: 3, Column: 5},
+			}, errParse),
+			expected: "" +
+				"error: errParse\n" +
+				" --> 3:5\n" +
+				"  |\n" +
+				"3 | last\n" +
+				"  |     ^\n",
+		},
+		{
+			name: "no source",
+			err: &Error{
+				Severity: SeverityWarning,
+				Span:     span(0, 1),
+				Message:  "foo",
+			},
+			expected: "" +
+				"warning: foo\n" +
+				" --> 1:1\n",
+		},
+		{
+			name:     "not an Error",
+			src:      src,
+			err:      errParse,
+			expected: "error: errParse\n",
+		},
+		{
+			name: "wrapped",
+			src:  src,
+			err:  fmt.Errorf("wrapped: %w", NewError(span(0, 5), errParse)),
+			expected: "" +
+				"error: errParse\n" +
+				" --> 1:1\n" +
+				"  |\n" +
+				"1 | first line\n" +
+				"  | ^^^^^\n",
+		},
+		{
+			name: "joined",
+			src:  src,
+			err:  errors.Join(NewError(span(0, 5), errParse), errState),
+			expected: "" +
+				"error: errParse\n" +
+				" --> 1:1\n" +
+				"  |\n" +
+				"1 | first line\n" +
+				"  | ^^^^^\n" +
+				"error: errState\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			if err := RenderError(&b, tc.src, tc.err); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("RenderError (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCustomLexer_Err_span(t *testing.T) {
+	t.Parallel()
+
+	l := NewCustomLexer(strings.NewReader("Hello World"), LexStateFn(
+		func(ctx *CustomLexerContext) (LexState, error) {
+			ctx.Discard()
+			ctx.AdvanceN(3)
+
+			return nil, errState
+		},
+	))
+
+	_ = l.NextToken(context.Background())
+
+	var got *Error
+	if !errors.As(l.Err(), &got) {
+		t.Fatalf("expected *Error, got %T", l.Err())
+	}
+
+	wantSpan := Span{
+		Start: Position{Offset: 1, Line: 1, Column: 2},
+		End:   Position{Offset: 4, Line: 1, Column: 5},
+	}
+	if diff := cmp.Diff(wantSpan, got.Span); diff != "" {
+		t.Errorf("Span (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(errState, got.Err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Err (-want +got):\n%s", diff)
+	}
+}
+
+func TestCustomLexer_Err_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	l := NewCustomLexer(strings.NewReader("Hello World"), &lexWordState{})
+	_ = l.NextToken(ctx)
+
+	// Context errors are not wrapped.
+	if diff := cmp.Diff(context.Canceled, l.Err(), cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Err (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanningLexer_Err_span(t *testing.T) {
+	t.Parallel()
+
+	l := NewScanningLexer(strings.NewReader(`foo "bar`))
+	l.SetFilename("test.txt")
+
+	for l.NextToken(context.Background()).Type != TokenTypeEOF {
+	}
+
+	var got *Error
+	if !errors.As(l.Err(), &got) {
+		t.Fatalf("expected *Error, got %T", l.Err())
+	}
+
+	wantSpan := Span{
+		Start: Position{Filename: "test.txt", Offset: 4, Line: 1, Column: 5},
+		End:   Position{Filename: "test.txt", Offset: 8, Line: 1, Column: 9},
+	}
+	if diff := cmp.Diff(wantSpan, got.Span); diff != "" {
+		t.Errorf("Span (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff("literal not terminated", got.Message); diff != "" {
+		t.Errorf("Message (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(errScanner, got.Err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Err (-want +got):\n%s", diff)
+	}
+}
+
+func TestParser_Parse_error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "wrapped",
+			err:      errParse,
+			expected: "1:7: errParse",
+		},
+		{
+			name:     "already wrapped",
+			err:      fmt.Errorf("wrapped: %w", NewError(span(0, 1), errParse)),
+			expected: "wrapped: 1:1: errParse",
+		},
+		{
+			name:     "context error",
+			err:      context.DeadlineExceeded,
+			expected: context.DeadlineExceeded.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := NewCustomLexer(strings.NewReader("Hello World"), &lexWordState{})
+			p := NewParser(l, ParseStateFn(func(ctx *ParserContext[string]) error {
+				_ = ctx.Next()
+				_ = ctx.Next()
+
+				return tc.err
+			}))
+
+			_, got := p.Parse(context.Background())
+			if !errors.Is(got, tc.err) {
+				t.Errorf("expected error %v, got %v", tc.err, got)
+			}
+
+			if diff := cmp.Diff(tc.expected, got.Error()); diff != "" {
+				t.Errorf("Parse (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// lexDigitsState lexes words of digits. Other characters are emitted as error
+// tokens.
+type lexDigitsState struct{}
+
+//nolint:ireturn // returning interface is required to satisfy LexState.
+func (s *lexDigitsState) Run(ctx *CustomLexerContext) (LexState, error) {
+	rn := ctx.NextRune()
+
+	switch {
+	case rn == EOF:
+		return nil, io.EOF
+	case rn == ' ':
+		ctx.Ignore()
+	case rn >= '0' && rn <= '9':
+		ctx.Emit(wordType)
+	default:
+		ctx.EmitError(fmt.Errorf("%w: %q", errState, rn))
+	}
+
+	return s, nil
+}
+
+// parseReportState adds a node for each token and reports error tokens.
+type parseReportState struct{}
+
+func (s *parseReportState) Run(ctx *ParserContext[string]) error {
+	switch token := ctx.Next(); token.Type {
+	case TokenTypeEOF:
+		return nil
+	case TokenTypeError:
+		ctx.Report(token.Err)
+	default:
+		ctx.Node(token.Value)
+	}
+
+	ctx.PushState(s)
+
+	return nil
+}
+
+func TestCustomLexerContext_EmitError(t *testing.T) {
+	t.Parallel()
+
+	l := NewCustomLexer(strings.NewReader("1 x 2 y 3"), &lexDigitsState{})
+
+	got, err := LexParseSync(t.Context(), l, &parseReportState{})
+
+	var values []string
+	for _, child := range got.Children {
+		values = append(values, child.Value)
+	}
+
+	if diff := cmp.Diff([]string{"1", "2", "3"}, values); diff != "" {
+		t.Errorf("Children (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff("1:3: errState: 'x'\n1:7: errState: 'y'", err.Error()); diff != "" {
+		t.Errorf("Error (-want +got):\n%s", diff)
+	}
+
+	// Error tokens do not stop the lexer.
+	if diff := cmp.Diff(nil, l.Err(), cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Err (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanningLexer_SetErrorTokens(t *testing.T) {
+	t.Parallel()
+
+	l := NewScanningLexer(strings.NewReader("a 'bc' d \"e\nf"))
+	l.SetErrorTokens(true)
+
+	var (
+		values []string
+		errs   []string
+	)
+
+	for {
+		token := l.NextToken(context.Background())
+		if token.Type == TokenTypeEOF {
+			break
+		}
+
+		if token.Type == TokenTypeError {
+			errs = append(errs, token.Err.Error())
+			continue
+		}
+
+		values = append(values, token.Value)
+	}
+
+	if diff := cmp.Diff([]string{"a", "d", "f"}, values); diff != "" {
+		t.Errorf("values (-want +got):\n%s", diff)
+	}
+
+	wantErrs := []string{
+		"1:3: scanner error: invalid char literal",
+		"1:10: scanner error: literal not terminated",
+	}
+	if diff := cmp.Diff(wantErrs, errs); diff != "" {
+		t.Errorf("errors (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(nil, l.Err(), cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Err (-want +got):\n%s", diff)
+	}
+}
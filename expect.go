@@ -0,0 +1,121 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Expect consumes and returns the next token if it has type typ and, if any
+// values are given, one of the given values. Otherwise, the token is not
+// consumed and an [*Error] at the token is returned with a message describing
+// the expected and actual tokens, such as `expected '=', got Ident "name"`.
+// The cause of the error is [io.ErrUnexpectedEOF] at the end of the input and
+// [ErrUnexpectedToken] otherwise.
+func (ctx *ParserContext[V]) Expect(typ TokenType, values ...string) (*Token, error) {
+	return ctx.p.expect(ctx, typ, values)
+}
+
+// Accept consumes and returns the next token if it has type typ and, if any
+// values are given, one of the given values. Otherwise, the token is not
+// consumed and Accept returns false.
+func (ctx *ParserContext[V]) Accept(typ TokenType, values ...string) (*Token, bool) {
+	return ctx.p.accept(ctx, typ, values)
+}
+
+// AcceptAny consumes and returns the next token if it has one of the given
+// types. Otherwise, the token is not consumed and AcceptAny returns false.
+func (ctx *ParserContext[V]) AcceptAny(types ...TokenType) (*Token, bool) {
+	return ctx.p.acceptAny(ctx, types)
+}
+
+func (p *Parser[V]) expect(ctx context.Context, typ TokenType, values []string) (*Token, error) {
+	if token, ok := p.accept(ctx, typ, values); ok {
+		return token, nil
+	}
+
+	token := p.peek(ctx)
+
+	cause := ErrUnexpectedToken
+	if token.Type == TokenTypeEOF {
+		cause = io.ErrUnexpectedEOF
+	}
+
+	err := NewError(token.Span(), cause)
+	err.Message = "expected " + describeExpected(typ, values) + ", got " + describeToken(token, len(values) > 0)
+
+	return nil, err
+}
+
+func (p *Parser[V]) accept(ctx context.Context, typ TokenType, values []string) (*Token, bool) {
+	token := p.peek(ctx)
+	if token.Type != typ || (len(values) > 0 && !slices.Contains(values, token.Value)) {
+		return nil, false
+	}
+
+	return p.nextToken(ctx), true
+}
+
+func (p *Parser[V]) acceptAny(ctx context.Context, types []TokenType) (*Token, bool) {
+	if !slices.Contains(types, p.peek(ctx).Type) {
+		return nil, false
+	}
+
+	return p.nextToken(ctx), true
+}
+
+// describeExpected describes a token of type typ with one of the given values
+// for use in error messages.
+func describeExpected(typ TokenType, values []string) string {
+	if len(values) == 0 {
+		return typ.String()
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, strconv.Quote(v))
+	}
+
+	if len(quoted) <= 2 { //nolint:mnd // "a or b" doesn't need commas.
+		return strings.Join(quoted, " or ")
+	}
+
+	return strings.Join(quoted[:len(quoted)-1], ", ") + ", or " + quoted[len(quoted)-1]
+}
+
+// describeToken describes token for use in error messages. If valueOnly is
+// true, only the token's value is included.
+func describeToken(token *Token, valueOnly bool) string {
+	if token.Type == TokenTypeEOF {
+		return "<EOF>"
+	}
+
+	value := strconv.Quote(token.Value)
+	if valueOnly {
+		return value
+	}
+
+	name := token.Type.String()
+	if token.Value == string(rune(token.Type)) {
+		// The value is the same as the type name for single-rune types.
+		return name
+	}
+
+	return name + " " + value
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newScanningTestContext returns a new ParserContext reading input with a
+// ScanningLexer.
+func newScanningTestContext(input string) *ParserContext[string] {
+	return &ParserContext[string]{
+		Context: context.Background(),
+		p:       NewParser[string](NewScanningLexer(strings.NewReader(input)), nil),
+	}
+}
+
+func TestParserContext_Expect(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input   string
+		typ     TokenType
+		values  []string
+		want    string
+		wantErr error
+		wantMsg string
+	}{
+		"type": {
+			input: "name = 1",
+			typ:   TokenTypeIdent,
+			want:  "name",
+		},
+		"value": {
+			input:  "name = 1",
+			typ:    TokenTypeIdent,
+			values: []string{"key", "name"},
+			want:   "name",
+		},
+		"wrong type": {
+			input:   "name = 1",
+			typ:     '=',
+			wantErr: ErrUnexpectedToken,
+			wantMsg: `1:1: unexpected token: expected '=', got Ident "name"`,
+		},
+		"wrong rune type": {
+			input:   "= 1",
+			typ:     TokenTypeIdent,
+			wantErr: ErrUnexpectedToken,
+			wantMsg: `1:1: unexpected token: expected Ident, got '='`,
+		},
+		"wrong value": {
+			input:   "name = 1",
+			typ:     TokenTypeIdent,
+			values:  []string{"if", "else", "endif"},
+			wantErr: ErrUnexpectedToken,
+			wantMsg: `1:1: unexpected token: expected "if", "else", or "endif", got "name"`,
+		},
+		"eof": {
+			input:   "",
+			typ:     TokenTypeInt,
+			wantErr: io.ErrUnexpectedEOF,
+			wantMsg: `1:1: unexpected EOF: expected Int, got <EOF>`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := newScanningTestContext(tc.input)
+			next := ctx.Peek()
+
+			token, err := ctx.Expect(tc.typ, tc.values...)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Expect: want %v, got %v", tc.wantErr, err)
+			}
+
+			if err != nil {
+				if diff := cmp.Diff(tc.wantMsg, err.Error()); diff != "" {
+					t.Errorf("Error (-want +got):\n%s", diff)
+				}
+
+				// The token is not consumed.
+				if ctx.Peek() != next {
+					t.Errorf("Peek: want %v, got %v", next, ctx.Peek())
+				}
+
+				return
+			}
+
+			if diff := cmp.Diff(tc.want, token.Value); diff != "" {
+				t.Errorf("Expect (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParserContext_Accept(t *testing.T) {
+	t.Parallel()
+
+	ctx := newScanningTestContext("a = 1")
+
+	if token, ok := ctx.Accept('='); ok {
+		t.Errorf("Accept('='): want false, got %v", token)
+	}
+
+	if token, ok := ctx.Accept(TokenTypeIdent, "b"); ok {
+		t.Errorf("Accept(Ident, \"b\"): want false, got %v", token)
+	}
+
+	if token, ok := ctx.Accept(TokenTypeIdent, "a"); !ok || token.Value != "a" {
+		t.Errorf("Accept(Ident, \"a\"): want a, got %v, %v", token, ok)
+	}
+
+	if token, ok := ctx.AcceptAny(TokenTypeInt, TokenTypeFloat); ok {
+		t.Errorf("AcceptAny(Int, Float): want false, got %v", token)
+	}
+
+	if token, ok := ctx.AcceptAny(':', '='); !ok || token.Value != "=" {
+		t.Errorf("AcceptAny(':', '='): want =, got %v, %v", token, ok)
+	}
+
+	if diff := cmp.Diff("1", ctx.Next().Value); diff != "" {
+		t.Errorf("Next (-want +got):\n%s", diff)
+	}
+}
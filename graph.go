@@ -0,0 +1,193 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// graphNode is a node of a tree being written as a graph.
+type graphNode struct {
+	// id is the node's unique identifier in the graph.
+	id string
+
+	// label is the node's label including its start position.
+	label string
+
+	// parent is the id of the node's parent or empty for the root node.
+	parent string
+
+	// highlight is true if the node is on a highlighted path.
+	highlight bool
+}
+
+// graphNodes returns the nodes of the tree rooted at root in depth-first
+// order. Nodes on the path from root to any of the highlight nodes are
+// highlighted.
+func graphNodes[V comparable](root *Node[V], label func(V) string, highlight []*Node[V]) []graphNode {
+	if label == nil {
+		label = func(v V) string {
+			return fmt.Sprint(v)
+		}
+	}
+
+	onPath := map[*Node[V]]bool{}
+
+	for _, h := range highlight {
+		for n := h; n != nil; n = n.Parent {
+			onPath[n] = true
+			if n == root {
+				break
+			}
+		}
+	}
+
+	var nodes []graphNode
+
+	ids := map[*Node[V]]string{}
+
+	for n := range root.All() {
+		id := "n" + strconv.Itoa(len(nodes))
+		ids[n] = id
+
+		gn := graphNode{
+			id:        id,
+			label:     label(n.Value) + "\n" + n.Start.String(),
+			highlight: onPath[n],
+		}
+
+		if n != root {
+			gn.parent = ids[n.Parent]
+		}
+
+		nodes = append(nodes, gn)
+	}
+
+	return nodes
+}
+
+// WriteDOT writes the tree rooted at root to w as a Graphviz DOT graph. Each
+// node is labeled with the string returned by label and its start position.
+// Values are formatted with [fmt.Sprint] if label is nil.
+//
+// The nodes and edges on the path from root to each of the highlight nodes are
+// highlighted, for example to show the location of an error.
+func WriteDOT[V comparable](w io.Writer, root *Node[V], label func(V) string, highlight ...*Node[V]) error {
+	var b strings.Builder
+
+	b.WriteString("digraph {\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for _, n := range graphNodes(root, label, highlight) {
+		b.WriteString("  " + n.id + " [label=" + dotQuote(n.label))
+		if n.highlight {
+			b.WriteString(", color=red, penwidth=2")
+		}
+
+		b.WriteString("];\n")
+
+		if n.parent != "" {
+			b.WriteString("  " + n.parent + " -> " + n.id)
+			if n.highlight {
+				b.WriteString(" [color=red, penwidth=2]")
+			}
+
+			b.WriteString(";\n")
+		}
+	}
+
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing DOT graph: %w", err)
+	}
+
+	return nil
+}
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+	return `"` + r.Replace(s) + `"`
+}
+
+// WriteMermaid writes the tree rooted at root to w as a Mermaid flowchart. Each
+// node is labeled with the string returned by label and its start position.
+// Values are formatted with [fmt.Sprint] if label is nil.
+//
+// The nodes and edges on the path from root to each of the highlight nodes are
+// highlighted, for example to show the location of an error.
+func WriteMermaid[V comparable](w io.Writer, root *Node[V], label func(V) string, highlight ...*Node[V]) error {
+	var (
+		b          strings.Builder
+		classes    []string
+		linkStyles []string
+	)
+
+	b.WriteString("graph TD\n")
+
+	edge := 0
+
+	for _, n := range graphNodes(root, label, highlight) {
+		b.WriteString("    " + n.id + "[" + mermaidQuote(n.label) + "]\n")
+
+		if n.highlight {
+			classes = append(classes, n.id)
+		}
+
+		if n.parent != "" {
+			b.WriteString("    " + n.parent + " --> " + n.id + "\n")
+
+			if n.highlight {
+				linkStyles = append(linkStyles, strconv.Itoa(edge))
+			}
+
+			edge++
+		}
+	}
+
+	if len(classes) > 0 {
+		b.WriteString("    classDef highlight stroke:#f00,stroke-width:2px\n")
+		b.WriteString("    class " + strings.Join(classes, ",") + " highlight\n")
+	}
+
+	if len(linkStyles) > 0 {
+		b.WriteString("    linkStyle " + strings.Join(linkStyles, ",") + " stroke:#f00,stroke-width:2px\n")
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing Mermaid graph: %w", err)
+	}
+
+	return nil
+}
+
+// mermaidQuote returns s as a quoted Mermaid label. Characters that have a
+// special meaning in Mermaid labels are written as entity codes.
+func mermaidQuote(s string) string {
+	r := strings.NewReplacer(
+		`#`, "#35;",
+		`"`, "#quot;",
+		`<`, "#lt;",
+		`>`, "#gt;",
+		"\n", "<br>",
+		"\r", "",
+	)
+
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newGraphTestTree returns a tree for the expression `1 + "a"`.
+func newGraphTestTree() *Node[string] {
+	return addParent(&Node[string]{
+		Value: "+",
+		Start: Position{Offset: 2, Line: 1, Column: 3},
+		Children: []*Node[string]{
+			{
+				Value: "1",
+				Start: Position{Offset: 0, Line: 1, Column: 1},
+			},
+			{
+				Value: `"a"`,
+				Start: Position{Offset: 4, Line: 1, Column: 5},
+			},
+		},
+	})
+}
+
+func TestWriteDOT(t *testing.T) {
+	t.Parallel()
+
+	root := newGraphTestTree()
+
+	testCases := []struct {
+		name      string
+		highlight []*Node[string]
+		want      string
+	}{
+		{
+			name: "no highlight",
+			want: `digraph {
+  node [shape=box];
+  n0 [label="+\n1:3"];
+  n1 [label="1\n1:1"];
+  n0 -> n1;
+  n2 [label="\"a\"\n1:5"];
+  n0 -> n2;
+}
+`,
+		},
+		{
+			name:      "highlight",
+			highlight: []*Node[string]{root.Children[1]},
+			want: `digraph {
+  node [shape=box];
+  n0 [label="+\n1:3", color=red, penwidth=2];
+  n1 [label="1\n1:1"];
+  n0 -> n1;
+  n2 [label="\"a\"\n1:5", color=red, penwidth=2];
+  n0 -> n2 [color=red, penwidth=2];
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			if err := WriteDOT(&b, root, nil, tc.highlight...); err != nil {
+				t.Fatalf("WriteDOT: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, b.String()); diff != "" {
+				t.Errorf("WriteDOT (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	t.Parallel()
+
+	root := newGraphTestTree()
+
+	testCases := []struct {
+		name      string
+		highlight []*Node[string]
+		want      string
+	}{
+		{
+			name: "no highlight",
+			want: `graph TD
+    n0["+<br>1:3"]
+    n1["1<br>1:1"]
+    n0 --> n1
+    n2["#quot;a#quot;<br>1:5"]
+    n0 --> n2
+`,
+		},
+		{
+			name:      "highlight",
+			highlight: []*Node[string]{root.Children[1]},
+			want: `graph TD
+    n0["+<br>1:3"]
+    n1["1<br>1:1"]
+    n0 --> n1
+    n2["#quot;a#quot;<br>1:5"]
+    n0 --> n2
+    classDef highlight stroke:#f00,stroke-width:2px
+    class n0,n2 highlight
+    linkStyle 1 stroke:#f00,stroke-width:2px
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			if err := WriteMermaid(&b, root, nil, tc.highlight...); err != nil {
+				t.Fatalf("WriteMermaid: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, b.String()); diff != "" {
+				t.Errorf("WriteMermaid (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,18 +18,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"strconv"
 	"strings"
+	"testing"
 
 	"github.com/ianlewis/lexparse"
 )
 
 var (
-	errUnexpectedIdentifier = errors.New("unexpected identifier")
-	errUnclosedParen        = errors.New("unclosed parenthesis")
-	errUnexpectedParen      = errors.New("unexpected closing parenthesis")
-	errDivByZero            = errors.New("division by zero")
+	errUnexpectedParen = errors.New("unexpected closing parenthesis")
+	errDivByZero       = errors.New("division by zero")
 
 	errInvalidNode = errors.New("invalid node")
 )
@@ -51,21 +49,6 @@ type exprNode struct {
 	oper string  // Only used for nodeTypeOper.
 }
 
-func (n *exprNode) precedence() int {
-	if n.typ != nodeTypeOper {
-		panic(fmt.Sprintf("node %v is not an operator node", n))
-	}
-
-	switch n.oper {
-	case "+", "-":
-		return 1
-	case "*", "/":
-		return 2
-	default:
-		return 0
-	}
-}
-
 func (n *exprNode) String() string {
 	switch n.typ {
 	case nodeTypeNum:
@@ -77,108 +60,58 @@ func (n *exprNode) String() string {
 	}
 }
 
-func tokenErr(err error, t *lexparse.Token) error {
-	return fmt.Errorf("%w: %q, line %d, column %d", err,
-		t.Value, t.Start.Line, t.Start.Column)
-}
-
-// pratt implements a Pratt operator-precedence parser for infix expressions.
-func pratt(ctx *lexparse.ParserContext[*exprNode]) error {
-	n, err := parseExpr(ctx, 0, 0)
-	ctx.SetRoot(n)
-
-	return err
-}
+// newCalcParser returns a Pratt parser for infix arithmetic expressions.
+func newCalcParser() *lexparse.Pratt[*exprNode] {
+	p := lexparse.NewPratt[*exprNode]()
 
-func parseExpr(
-	ctx *lexparse.ParserContext[*exprNode],
-	depth, minPrecedence int,
-) (*lexparse.Node[*exprNode], error) {
-	// Check if the context is canceled.
-	select {
-	case <-ctx.Done():
-		//nolint:wrapcheck // We want to return the original context error.
-		return nil, ctx.Err()
-	default:
-	}
-
-	token := ctx.Next()
-
-	var lhs *lexparse.Node[*exprNode]
-
-	switch token.Type {
-	case lexparse.TokenTypeFloat, lexparse.TokenTypeInt:
+	num := func(token *lexparse.Token) (*exprNode, error) {
 		num, err := strconv.ParseFloat(token.Value, 64)
 		if err != nil {
-			return nil, tokenErr(err, token)
+			return nil, err //nolint:wrapcheck // The error is wrapped by Pratt.
 		}
 
-		lhs = ctx.NewNode(&exprNode{
+		return &exprNode{
 			typ: nodeTypeNum,
 			num: num,
-		})
-	case '(':
-		// Parse the expression inside the parentheses.
-		lhs2, err := parseExpr(ctx, depth+1, 0)
-		if err != nil {
-			return nil, err
-		}
-
-		lhs = lhs2
+		}, nil
+	}
 
-		t2 := ctx.Next()
-		if t2.Type != ')' {
-			return nil, tokenErr(errUnclosedParen, t2)
+	oper := func(token *lexparse.Token) *exprNode {
+		return &exprNode{
+			typ:  nodeTypeOper,
+			oper: token.Value,
 		}
-	case lexparse.TokenTypeEOF:
-		return nil, tokenErr(io.ErrUnexpectedEOF, token)
-	default:
-		return nil, tokenErr(errUnexpectedIdentifier, token)
 	}
 
-outerL:
-	for {
-		var opVal *exprNode
+	p.Literal(lexparse.TokenTypeFloat, num)
+	p.Literal(lexparse.TokenTypeInt, num)
+	p.Group('(', ')')
+	p.Binary('+', 1, lexparse.LeftAssoc, oper)
+	p.Binary('-', 1, lexparse.LeftAssoc, oper)
+	p.Binary('*', 2, lexparse.LeftAssoc, oper)
+	p.Binary('/', 2, lexparse.LeftAssoc, oper)
 
-		opToken := ctx.Peek()
-		switch opToken.Type {
-		case '+', '-', '*', '/':
-			opVal = &exprNode{
-				typ:  nodeTypeOper,
-				oper: opToken.Value,
-			}
-		case lexparse.TokenTypeEOF:
-			break outerL
-		case ')':
-			if depth == 0 {
-				return nil, tokenErr(errUnexpectedParen, opToken)
-			}
-
-			break outerL
-		default:
-			return nil, tokenErr(errUnexpectedIdentifier, opToken)
-		}
-
-		if opVal.precedence() < minPrecedence {
-			// If the operator precedence is less than the minimum precedence,
-			// stop parsing.
-			return lhs, nil
-		}
+	return p
+}
 
-		_ = ctx.Next() // Consume the operator token.
-		opNode := ctx.NewNode(opVal)
+// pratt parses an infix expression and sets it as the root of the tree.
+func pratt(ctx *lexparse.ParserContext[*exprNode]) error {
+	n, err := newCalcParser().Expr(ctx, 0)
+	if err != nil {
+		return err //nolint:wrapcheck // Expr returns a *lexparse.Error.
+	}
 
-		rhs, err := parseExpr(ctx, depth, opNode.Value.precedence())
-		if err != nil {
-			return nil, err
-		}
+	ctx.SetRoot(n)
 
-		// Add the operator's children.
-		opNode.Children = append(opNode.Children, lhs, rhs)
-		lhs = opNode
+	// The whole input must be a single expression.
+	switch token := ctx.Peek(); token.Type {
+	case lexparse.TokenTypeEOF:
+		return nil
+	case ')':
+		return lexparse.NewTokenError(token, errUnexpectedParen)
+	default:
+		return lexparse.NewTokenError(token, lexparse.ErrUnexpectedToken)
 	}
-
-	return lhs, nil
 }
 
 // Calculate performs calculation based on the parsed expression tree.
@@ -250,15 +183,47 @@ func Example_infixCalculator() {
 	fmt.Print(txt)
 
 	// Output:
-	// - (1:27)
-	// ├── * (1:5)
-	// │   ├── 6.1 (1:1)
-	// │   └── / (1:21)
-	// │       ├── + (1:13)
-	// │       │   ├── 2.8 (1:9)
-	// │       │   └── 3.2 (1:15)
-	// │       └── 7.6 (1:23)
+	// - (1:1)
+	// ├── / (1:1)
+	// │   ├── * (1:1)
+	// │   │   ├── 6.1 (1:1)
+	// │   │   └── + (1:9)
+	// │   │       ├── 2.8 (1:9)
+	// │   │       └── 3.2 (1:15)
+	// │   └── 7.6 (1:23)
 	// └── 2.4 (1:29)
 	//
-	// 2.4157894736842107
+	// 2.41578947368421
+}
+
+// BenchmarkInfixCalculator compares the concurrent and synchronous modes of
+// lexing and parsing infix expressions.
+func BenchmarkInfixCalculator(b *testing.B) {
+	input := strings.Repeat("6.1 * ( 2.8 + 3.2 ) / 7.6 - ", 1000) + "2.4"
+
+	b.Run("LexParse", func(b *testing.B) {
+		for b.Loop() {
+			_, err := lexparse.LexParse(
+				context.Background(),
+				lexparse.NewScanningLexer(strings.NewReader(input)),
+				lexparse.ParseStateFn(pratt),
+			)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("LexParseSync", func(b *testing.B) {
+		for b.Loop() {
+			_, err := lexparse.LexParseSync(
+				context.Background(),
+				lexparse.NewScanningLexer(strings.NewReader(input)),
+				lexparse.ParseStateFn(pratt),
+			)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
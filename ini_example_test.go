@@ -19,8 +19,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 	"strings"
+	"testing"
 
 	"github.com/ianlewis/lexparse"
 )
@@ -151,12 +153,6 @@ func lexINIComment(ctx *lexparse.CustomLexerContext) (lexparse.LexState, error)
 	return lexparse.LexStateFn(lexINI), nil
 }
 
-// iniTokenErr formats an error message with token context.
-func iniTokenErr(err error, t *lexparse.Token) error {
-	return fmt.Errorf("%w: %q, line %d, column %d", err,
-		t.Value, t.Start.Line, t.Start.Column)
-}
-
 // parseINIInit is the initial parser state for INI files.
 func parseINIInit(ctx *lexparse.ParserContext[*iniNode]) error {
 	// Replace the root node with a new root node.
@@ -190,7 +186,7 @@ func parseINI(ctx *lexparse.ParserContext[*iniNode]) error {
 	case lexparse.TokenTypeEOF:
 		return nil
 	default:
-		return iniTokenErr(errINIIdentifier, t)
+		return lexparse.NewTokenError(t, errINIIdentifier)
 	}
 
 	return nil
@@ -198,26 +194,25 @@ func parseINI(ctx *lexparse.ParserContext[*iniNode]) error {
 
 // parseSection parses a section header.
 func parseSection(ctx *lexparse.ParserContext[*iniNode]) error {
-	openBracket := ctx.Next()
-	if openBracket.Type != lexINITypeOper || openBracket.Value != "[" {
-		return iniTokenErr(errINIIdentifier, openBracket)
+	if _, err := ctx.Expect(lexINITypeOper, "["); err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
 	}
 
-	sectionToken := ctx.Next()
-	if sectionToken.Type != lexINITypeIden {
-		return iniTokenErr(errINIIdentifier, sectionToken)
+	sectionToken, err := ctx.Expect(lexINITypeIden)
+	if err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
 	}
 
-	closeBracket := ctx.Next()
-	if closeBracket.Type != lexINITypeOper || closeBracket.Value != "]" {
-		return iniTokenErr(errINIIdentifier, closeBracket)
+	if _, err := ctx.Expect(lexINITypeOper, "]"); err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
 	}
 
 	sectionName := strings.TrimSpace(sectionToken.Value)
 
-	// Validate the section name.
+	// Validate the section name. The error is reported so that parsing can
+	// continue and find any other errors.
 	if !iniIdenRegexp.MatchString(sectionName) {
-		return iniTokenErr(errINISectionName, sectionToken)
+		ctx.Report(lexparse.NewTokenError(sectionToken, errINISectionName))
 	}
 
 	// Create a new node for the section and push it onto the parse tree.
@@ -235,26 +230,29 @@ func parseSection(ctx *lexparse.ParserContext[*iniNode]) error {
 
 // parseProperty parses a property key-value pair.
 func parseProperty(ctx *lexparse.ParserContext[*iniNode]) error {
-	keyToken := ctx.Next()
-	if keyToken.Type != lexINITypeIden {
-		return iniTokenErr(errINIIdentifier, keyToken)
+	keyToken, err := ctx.Expect(lexINITypeIden)
+	if err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
 	}
 
 	keyName := strings.TrimSpace(keyToken.Value)
 
-	// Validate the property name.
-	if !iniIdenRegexp.MatchString(keyName) {
-		return iniTokenErr(errINIPropertyName, keyToken)
+	if _, err := ctx.Expect(lexINITypeOper, "="); err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
 	}
 
-	eqToken := ctx.Next()
-	if eqToken.Type != lexINITypeOper || eqToken.Value != "=" {
-		return iniTokenErr(errINIIdentifier, eqToken)
+	valueToken, err := ctx.Expect(lexINITypeValue)
+	if err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
 	}
 
-	valueToken := ctx.Next()
-	if valueToken.Type != lexINITypeValue {
-		return iniTokenErr(errINIIdentifier, valueToken)
+	// Validate the property name. The error is reported so that parsing can
+	// continue and find any other errors.
+	if !iniIdenRegexp.MatchString(keyName) {
+		ctx.Report(lexparse.NewTokenError(keyToken, errINIPropertyName))
+		ctx.PushState(lexparse.ParseStateFn(parseINI))
+
+		return nil
 	}
 
 	// Create a new node for the property and add it to the current section.
@@ -310,3 +308,86 @@ file = "payroll.dat"
 	//     ├── port = 143 (9:7)
 	//     └── file = "payroll.dat" (10:7)
 }
+
+// Example_iniParserErrors demonstrates reporting multiple errors in an INI
+// file. Invalid section and property names are reported and parsing continues
+// so that all of the errors are found in a single pass.
+func Example_iniParserErrors() {
+	input := `[owner]
+first name = John
+last.name = Doe
+
+[data base]
+server = 192.0.2.62
+`
+
+	_, err := lexparse.LexParse(
+		context.Background(),
+		lexparse.NewCustomLexer(strings.NewReader(input), lexparse.LexStateFn(lexINI)),
+		lexparse.ParseStateFn(parseINIInit),
+		lexparse.WithFilename("config.ini"),
+	)
+
+	src := lexparse.NewSourceFile("config.ini", []byte(input))
+	if err := lexparse.RenderError(os.Stdout, src, err); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// error: invalid property name: "first name "
+	//  --> config.ini:2:1
+	//   |
+	// 2 | first name = John
+	//   | ^^^^^^^^^^^
+	// error: invalid property name: "last.name "
+	//  --> config.ini:3:1
+	//   |
+	// 3 | last.name = Doe
+	//   | ^^^^^^^^^^
+	// error: invalid section name: "data base"
+	//  --> config.ini:5:2
+	//   |
+	// 5 | [data base]
+	//   |  ^^^^^^^^^
+}
+
+// iniBenchSection is a section of an INI file used in benchmarks.
+const iniBenchSection = `; comment
+[section]
+name = John Doe
+organization = Acme Widgets Inc.
+server = 192.0.2.62
+port = 143
+`
+
+// BenchmarkINIParser compares the concurrent and synchronous modes of
+// lexing and parsing INI files.
+func BenchmarkINIParser(b *testing.B) {
+	input := strings.Repeat(iniBenchSection, 1000)
+
+	b.Run("LexParse", func(b *testing.B) {
+		for b.Loop() {
+			_, err := lexparse.LexParse(
+				context.Background(),
+				lexparse.NewCustomLexer(strings.NewReader(input), lexparse.LexStateFn(lexINI)),
+				lexparse.ParseStateFn(parseINIInit),
+			)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("LexParseSync", func(b *testing.B) {
+		for b.Loop() {
+			_, err := lexparse.LexParseSync(
+				context.Background(),
+				lexparse.NewCustomLexer(strings.NewReader(input), lexparse.LexStateFn(lexINI)),
+				lexparse.ParseStateFn(parseINIInit),
+			)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
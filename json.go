@@ -0,0 +1,176 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonNode is the JSON representation of a [Node].
+type jsonNode struct {
+	Value    json.RawMessage `json:"value"`
+	Start    jsonPosition    `json:"start"`
+	End      jsonPosition    `json:"end"`
+	Children []*jsonNode     `json:"children,omitempty"`
+}
+
+// jsonPosition is the JSON representation of a [Position].
+type jsonPosition struct {
+	Filename string `json:"filename,omitempty"`
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+func newJSONPosition(p Position) jsonPosition {
+	return jsonPosition(p)
+}
+
+func (p jsonPosition) position() Position {
+	return Position(p)
+}
+
+// MarshalJSON implements [json.Marshaler]. Values are encoded with
+// [encoding/json]. See [MarshalTreeJSON] for the format.
+func (n *Node[V]) MarshalJSON() ([]byte, error) {
+	return MarshalTreeJSON(n, nil)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. Values are decoded with
+// [encoding/json]. See [MarshalTreeJSON] for the format. The Parent field of n
+// is not changed.
+func (n *Node[V]) UnmarshalJSON(data []byte) error {
+	root, err := UnmarshalTreeJSON[V](data, nil)
+	if err != nil {
+		return err
+	}
+
+	// Copy the decoded root into n and update the parent of its children.
+	root.Parent = n.Parent
+	*n = *root
+	for _, child := range n.Children {
+		child.Parent = n
+	}
+
+	return nil
+}
+
+// MarshalTreeJSON returns the JSON encoding of the tree rooted at root. Each
+// node is encoded as an object with "value", "start", "end", and "children"
+// fields. Positions are encoded as objects with "filename", "offset", "line",
+// and "column" fields. The filename is omitted if empty, as are the children
+// of leaf nodes.
+//
+// Values are encoded with [encoding/json]. If encode is not nil, the value
+// returned by encode for each node's value is encoded instead. This is useful
+// for value types that do not support [encoding/json].
+func MarshalTreeJSON[V comparable](root *Node[V], encode func(V) (any, error)) ([]byte, error) {
+	jn, err := newJSONNode(root, encode)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(jn)
+	if err != nil {
+		return nil, fmt.Errorf("encoding tree: %w", err)
+	}
+
+	return data, nil
+}
+
+func newJSONNode[V comparable](n *Node[V], encode func(V) (any, error)) (*jsonNode, error) {
+	var value any = n.Value
+	if encode != nil {
+		v, err := encode(n.Value)
+		if err != nil {
+			return nil, fmt.Errorf("encoding value %v: %w", n.Value, err)
+		}
+
+		value = v
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encoding value %v: %w", n.Value, err)
+	}
+
+	jn := &jsonNode{
+		Value: data,
+		Start: newJSONPosition(n.Start),
+		End:   newJSONPosition(n.End),
+	}
+
+	for _, child := range n.Children {
+		jc, err := newJSONNode(child, encode)
+		if err != nil {
+			return nil, err
+		}
+
+		jn.Children = append(jn.Children, jc)
+	}
+
+	return jn, nil
+}
+
+// UnmarshalTreeJSON decodes a tree encoded by [MarshalTreeJSON] and returns its
+// root. The Parent fields of the decoded nodes are set.
+//
+// Values are decoded with [encoding/json]. If decode is not nil, it is called
+// with the encoded value of each node and returns the decoded value instead.
+func UnmarshalTreeJSON[V comparable](data []byte, decode func(json.RawMessage) (V, error)) (*Node[V], error) {
+	var jn jsonNode
+	if err := json.Unmarshal(data, &jn); err != nil {
+		return nil, fmt.Errorf("decoding tree: %w", err)
+	}
+
+	return newNodeFromJSON[V](&jn, nil, decode)
+}
+
+func newNodeFromJSON[V comparable](
+	jn *jsonNode,
+	parent *Node[V],
+	decode func(json.RawMessage) (V, error),
+) (*Node[V], error) {
+	n := &Node[V]{
+		Parent: parent,
+		Start:  jn.Start.position(),
+		End:    jn.End.position(),
+	}
+
+	if decode != nil {
+		v, err := decode(jn.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decoding value %s: %w", jn.Value, err)
+		}
+
+		n.Value = v
+	} else if len(jn.Value) > 0 {
+		if err := json.Unmarshal(jn.Value, &n.Value); err != nil {
+			return nil, fmt.Errorf("decoding value %s: %w", jn.Value, err)
+		}
+	}
+
+	for _, jc := range jn.Children {
+		child, err := newNodeFromJSON(jc, n, decode)
+		if err != nil {
+			return nil, err
+		}
+
+		n.Children = append(n.Children, child)
+	}
+
+	return n, nil
+}
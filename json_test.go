@@ -0,0 +1,145 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// jsonTestValue is a value type that does not support encoding/json.
+type jsonTestValue struct {
+	name string
+}
+
+var errJSONTest = errors.New("test error")
+
+func TestNode_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	root := addParent(&Node[string]{
+		Value: "a",
+		Start: Position{Filename: "test.txt", Offset: 0, Line: 1, Column: 1},
+		End:   Position{Filename: "test.txt", Offset: 3, Line: 1, Column: 4},
+		Children: []*Node[string]{
+			{
+				Value: "b",
+				Start: Position{Offset: 2, Line: 1, Column: 3},
+				End:   Position{Offset: 3, Line: 1, Column: 4},
+			},
+		},
+	})
+
+	data, err := json.Marshal(root)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	want := `{"value":"a",` +
+		`"start":{"filename":"test.txt","offset":0,"line":1,"column":1},` +
+		`"end":{"filename":"test.txt","offset":3,"line":1,"column":4},` +
+		`"children":[{"value":"b",` +
+		`"start":{"offset":2,"line":1,"column":3},` +
+		`"end":{"offset":3,"line":1,"column":4}}]}`
+
+	if diff := cmp.Diff(want, string(data)); diff != "" {
+		t.Errorf("Marshal (-want +got):\n%s", diff)
+	}
+
+	var got Node[string]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if diff := cmp.Diff(root, &got); diff != "" {
+		t.Errorf("Unmarshal (-want +got):\n%s", diff)
+	}
+}
+
+func TestMarshalTreeJSON(t *testing.T) {
+	t.Parallel()
+
+	root := addParent(&Node[*jsonTestValue]{
+		Value: &jsonTestValue{name: "a"},
+		Children: []*Node[*jsonTestValue]{
+			{Value: &jsonTestValue{name: "b"}},
+			{Value: &jsonTestValue{name: "c"}},
+		},
+	})
+
+	data, err := MarshalTreeJSON(root, func(v *jsonTestValue) (any, error) {
+		return v.name, nil
+	})
+	if err != nil {
+		t.Fatalf("MarshalTreeJSON: %v", err)
+	}
+
+	got, err := UnmarshalTreeJSON(data, func(data json.RawMessage) (*jsonTestValue, error) {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return nil, err //nolint:wrapcheck // test helper.
+		}
+
+		return &jsonTestValue{name: name}, nil
+	})
+	if err != nil {
+		t.Fatalf("UnmarshalTreeJSON: %v", err)
+	}
+
+	label := func(v *jsonTestValue) string {
+		return v.name
+	}
+
+	var want, gotExpr strings.Builder
+
+	_ = WriteSExpr(&want, root, label)
+	_ = WriteSExpr(&gotExpr, got, label)
+
+	if diff := cmp.Diff(want.String(), gotExpr.String()); diff != "" {
+		t.Errorf("UnmarshalTreeJSON (-want +got):\n%s", diff)
+	}
+
+	checkParents(t, got)
+}
+
+func TestMarshalTreeJSON_errors(t *testing.T) {
+	t.Parallel()
+
+	root := &Node[string]{Value: "a"}
+
+	_, err := MarshalTreeJSON(root, func(string) (any, error) {
+		return nil, errJSONTest
+	})
+	if !errors.Is(err, errJSONTest) {
+		t.Errorf("MarshalTreeJSON: want %v, got %v", errJSONTest, err)
+	}
+
+	_, err = UnmarshalTreeJSON(
+		[]byte(`{"value":"a","children":[{"value":1}]}`),
+		func(json.RawMessage) (string, error) { return "", errJSONTest },
+	)
+	if !errors.Is(err, errJSONTest) {
+		t.Errorf("UnmarshalTreeJSON: want %v, got %v", errJSONTest, err)
+	}
+
+	var n Node[string]
+	if err := json.Unmarshal([]byte(`{"value":"a","children":[{"value":1}]}`), &n); err == nil {
+		t.Errorf("Unmarshal: expected error")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"strings"
+)
+
+// KeywordTable maps keywords to the token types used to classify identifiers
+// at lex time. A KeywordTable is not modified after it is created and may be
+// shared by multiple lexers.
+type KeywordTable struct {
+	keywords        map[string]TokenType
+	caseInsensitive bool
+}
+
+// NewKeywordTable returns a new KeywordTable for the given keywords. If
+// caseInsensitive is true, identifiers match keywords regardless of case.
+func NewKeywordTable(keywords map[string]TokenType, caseInsensitive bool) *KeywordTable {
+	t := &KeywordTable{
+		keywords:        make(map[string]TokenType, len(keywords)),
+		caseInsensitive: caseInsensitive,
+	}
+
+	for word, typ := range keywords {
+		t.keywords[t.key(word)] = typ
+	}
+
+	return t
+}
+
+// Lookup returns the token type of the keyword ident and true if ident is a
+// keyword. Otherwise it returns false. A nil KeywordTable has no keywords.
+func (t *KeywordTable) Lookup(ident string) (TokenType, bool) {
+	if t == nil {
+		return 0, false
+	}
+
+	typ, ok := t.keywords[t.key(ident)]
+
+	return typ, ok
+}
+
+// key returns the map key for word.
+func (t *KeywordTable) key(word string) string {
+	if t.caseInsensitive {
+		return strings.ToLower(word)
+	}
+
+	return word
+}
+
+// WithKeywords configures the lexer to return identifiers that are keywords in
+// the table as tokens of the keyword's type rather than [TokenTypeIdent]. The
+// token's Value is the identifier as it appears in the input.
+//
+// Token types for keywords should be outside the printable ASCII range unless
+// a name is registered with [RegisterTokenType]. Otherwise, they are shown as
+// characters in token dumps and error messages. See [TokenType].
+func WithKeywords(keywords *KeywordTable) ScanningLexerOption {
+	return func(o *scanningLexerOptions) {
+		o.keywords = keywords
+	}
+}
+
+// EmitIdentOrKeyword emits the token between the current cursor position and
+// reader position like [CustomLexerContext.Emit]. If the token's value is a
+// keyword in keywords, the token has the keyword's type. Otherwise, it has the
+// type ident.
+func (ctx *CustomLexerContext) EmitIdentOrKeyword(ident TokenType, keywords *KeywordTable) *Token {
+	typ := ident
+	if kw, ok := keywords.Lookup(ctx.Token()); ok {
+		typ = kw
+	}
+
+	return ctx.Emit(typ)
+}
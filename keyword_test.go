@@ -0,0 +1,192 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	kwTypeIf TokenType = iota + 3000
+	kwTypeElse
+	kwTypeEnd
+)
+
+// testKeywords returns the keywords used in tests.
+func testKeywords() map[string]TokenType {
+	return map[string]TokenType{
+		"if":   kwTypeIf,
+		"else": kwTypeElse,
+		"END":  kwTypeEnd,
+	}
+}
+
+// keywordTestToken is the type and value of a token.
+type keywordTestToken struct {
+	Type  TokenType
+	Value string
+}
+
+// lexKeywordTokens returns the types and values of the tokens returned by l.
+func lexKeywordTokens(t *testing.T, l Lexer) []keywordTestToken {
+	t.Helper()
+
+	var got []keywordTestToken
+
+	for {
+		tok := l.NextToken(context.Background())
+		if tok.Type == TokenTypeEOF {
+			break
+		}
+
+		got = append(got, keywordTestToken{tok.Type, tok.Value})
+	}
+
+	if err := l.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return got
+}
+
+func TestKeywordTable_Lookup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		table           *KeywordTable
+		ident           string
+		expectedType    TokenType
+		expectedKeyword bool
+	}{
+		{
+			name:            "keyword",
+			table:           NewKeywordTable(testKeywords(), false),
+			ident:           "if",
+			expectedType:    kwTypeIf,
+			expectedKeyword: true,
+		},
+		{
+			name:  "case sensitive",
+			table: NewKeywordTable(testKeywords(), false),
+			ident: "IF",
+		},
+		{
+			name:  "not keyword",
+			table: NewKeywordTable(testKeywords(), false),
+			ident: "ifx",
+		},
+		{
+			name:            "case insensitive",
+			table:           NewKeywordTable(testKeywords(), true),
+			ident:           "If",
+			expectedType:    kwTypeIf,
+			expectedKeyword: true,
+		},
+		{
+			name:            "case insensitive keyword",
+			table:           NewKeywordTable(testKeywords(), true),
+			ident:           "end",
+			expectedType:    kwTypeEnd,
+			expectedKeyword: true,
+		},
+		{
+			name:  "nil",
+			ident: "if",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			typ, ok := tc.table.Lookup(tc.ident)
+			if diff := cmp.Diff(tc.expectedType, typ); diff != "" {
+				t.Errorf("Lookup type (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expectedKeyword, ok); diff != "" {
+				t.Errorf("Lookup ok (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithKeywords(t *testing.T) {
+	t.Parallel()
+
+	l := NewScanningLexer(
+		strings.NewReader(`if x else "if" ifx END`),
+		WithKeywords(NewKeywordTable(testKeywords(), false)),
+	)
+
+	expected := []keywordTestToken{
+		{kwTypeIf, "if"},
+		{TokenTypeIdent, "x"},
+		{kwTypeElse, "else"},
+		{TokenTypeString, `"if"`},
+		{TokenTypeIdent, "ifx"},
+		{kwTypeEnd, "END"},
+	}
+
+	if diff := cmp.Diff(expected, lexKeywordTokens(t, l)); diff != "" {
+		t.Errorf("unexpected tokens (-want +got):\n%s", diff)
+	}
+}
+
+func TestCustomLexerContext_EmitIdentOrKeyword(t *testing.T) {
+	t.Parallel()
+
+	keywords := NewKeywordTable(testKeywords(), true)
+
+	var lexWord LexState
+
+	lexWord = LexStateFn(func(ctx *CustomLexerContext) (LexState, error) {
+		for unicode.IsSpace(ctx.Peek()) {
+			ctx.Discard()
+		}
+
+		if ctx.Peek() == EOF {
+			return nil, io.EOF
+		}
+
+		for unicode.IsLetter(ctx.Peek()) {
+			ctx.Advance()
+		}
+
+		ctx.EmitIdentOrKeyword(wordType, keywords)
+
+		return lexWord, nil
+	})
+
+	l := NewCustomLexer(strings.NewReader("IF x Else end"), lexWord)
+
+	expected := []keywordTestToken{
+		{kwTypeIf, "IF"},
+		{wordType, "x"},
+		{kwTypeElse, "Else"},
+		{kwTypeEnd, "end"},
+	}
+
+	if diff := cmp.Diff(expected, lexKeywordTokens(t, l)); diff != "" {
+		t.Errorf("unexpected tokens (-want +got):\n%s", diff)
+	}
+}
@@ -18,11 +18,69 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
+	"text/scanner"
+	"unicode"
+	"unicode/utf8"
 )
 
 // TokenType is a user-defined Token type.
+//
+// Positive values below 128 are the token types of single ASCII characters
+// such as '=' and are shown as the quoted character by [TokenType.String].
+// User-defined token types should use values outside the printable ASCII range,
+// such as iota + 1000, unless a name is registered with [RegisterTokenType].
 type TokenType int
 
+//nolint:gochecknoglobals // The registry is shared by all token types.
+var (
+	tokenTypeNamesMu sync.RWMutex
+	tokenTypeNames   = map[TokenType]string{}
+)
+
+// RegisterTokenType registers name as the name of typ returned by
+// [TokenType.String]. It is typically called when the token types for a
+// [Lexer] are defined. The registry is shared by all lexers so token types
+// from different lexers used in the same program should have distinct values.
+// Registering a name for a built-in token type overrides its default name.
+func RegisterTokenType(typ TokenType, name string) {
+	tokenTypeNamesMu.Lock()
+	defer tokenTypeNamesMu.Unlock()
+
+	tokenTypeNames[typ] = name
+}
+
+// String returns a readable name for the token type. Names registered with
+// [RegisterTokenType] are returned if present. Otherwise, built-in token types
+// are named as in [text/scanner], token types for printable ASCII characters
+// such as '=' are shown as the quoted character, and other token types are
+// shown as their numeric value.
+func (typ TokenType) String() string {
+	tokenTypeNamesMu.RLock()
+	name, ok := tokenTypeNames[typ]
+	tokenTypeNamesMu.RUnlock()
+
+	if ok {
+		return name
+	}
+
+	switch typ {
+	case TokenTypeError:
+		return "Error"
+	case TokenTypeEOF, TokenTypeIdent, TokenTypeInt, TokenTypeFloat, TokenTypeChar,
+		TokenTypeString, TokenTypeRawString, TokenTypeComment:
+		return scanner.TokenString(rune(typ))
+	}
+
+	// NOTE: Only ASCII characters are quoted since user-defined token types
+	// commonly have values that are also printable non-ASCII code points.
+	if typ > 0 && typ < utf8.RuneSelf && unicode.IsPrint(rune(typ)) {
+		return strconv.QuoteRune(rune(typ))
+	}
+
+	return fmt.Sprintf("TokenType(%d)", int(typ))
+}
+
 // Position represents a position in an input.
 type Position struct {
 	// Filename is the name of the file being read. It can be empty if the
@@ -35,8 +93,10 @@ type Position struct {
 	// Line is the line number in the input stream, starting at 1.
 	Line int
 
-	// Column is the column number in the line, starting at 1. It counts
-	// characters in the line, including whitespace and newlines.
+	// Column is the column number in the line, starting at 1. By default it
+	// counts Unicode code points in the line, including whitespace and
+	// newlines. Lexers can be configured to count columns differently. See
+	// [ColumnMode].
 	Column int
 }
 
@@ -62,16 +122,24 @@ type Token struct {
 
 	// End is the end position in the byte stream where the Token was found.
 	End Position
+
+	// Err is the error for tokens of type [TokenTypeError]. It is nil for
+	// other tokens.
+	Err error
 }
 
-// String returns a string representation of the Token.
-func (t Token) String() string {
-	value := t.Value
-	if t.Type == TokenTypeEOF {
-		value = "<EOF>"
+// Span returns the span of the input covered by the Token.
+func (t Token) Span() Span {
+	return Span{
+		Start: t.Start,
+		End:   t.End,
 	}
+}
 
-	return fmt.Sprintf("%s:%s: %s", t.Start, t.End, value)
+// String returns a string representation of the Token including its
+// position, type, and value.
+func (t Token) String() string {
+	return fmt.Sprintf("%s:%s: %s", t.Start, t.End, describeToken(&t, false))
 }
 
 // Lexer is an interface that defines the methods for a lexer that tokenizes
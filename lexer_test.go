@@ -0,0 +1,134 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testTokenTypeName is a token type with a registered name.
+const testTokenTypeName TokenType = -1000
+
+//nolint:gochecknoinits // Token type names are registered once for all tests.
+func init() {
+	RegisterTokenType(testTokenTypeName, "NAME")
+}
+
+// registerTestTokenType registers name for typ until the test completes. The
+// registry is global so tests that call it must not run in parallel.
+func registerTestTokenType(t *testing.T, typ TokenType, name string) {
+	t.Helper()
+
+	tokenTypeNamesMu.RLock()
+	prev, ok := tokenTypeNames[typ]
+	tokenTypeNamesMu.RUnlock()
+
+	RegisterTokenType(typ, name)
+
+	t.Cleanup(func() {
+		tokenTypeNamesMu.Lock()
+		defer tokenTypeNamesMu.Unlock()
+
+		if ok {
+			tokenTypeNames[typ] = prev
+		} else {
+			delete(tokenTypeNames, typ)
+		}
+	})
+}
+
+func TestTokenType_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[TokenType]string{
+		TokenTypeEOF:       "EOF",
+		TokenTypeIdent:     "Ident",
+		TokenTypeRawString: "RawString",
+		TokenTypeComment:   "Comment",
+		TokenTypeError:     "Error",
+		'=':                "'='",
+		'\n':               "TokenType(10)",
+		'~':                "'~'",
+		'é':                "TokenType(233)",
+		1000:               "TokenType(1000)",
+		2000:               "TokenType(2000)",
+		100000000:          "TokenType(100000000)",
+		testTokenTypeName:  "NAME",
+	}
+
+	for typ, want := range testCases {
+		if diff := cmp.Diff(want, typ.String()); diff != "" {
+			t.Errorf("TokenType(%d).String() (-want +got):\n%s", int(typ), diff)
+		}
+	}
+}
+
+// TestRegisterTokenType_builtin overrides the name of a built-in token type.
+// It does not run in parallel since other tests use the built-in names.
+//
+//nolint:paralleltest // The token type registry is global.
+func TestRegisterTokenType_builtin(t *testing.T) {
+	registerTestTokenType(t, TokenTypeComment, "COMMENT")
+
+	if diff := cmp.Diff("COMMENT", TokenTypeComment.String()); diff != "" {
+		t.Errorf("TokenTypeComment.String() (-want +got):\n%s", diff)
+	}
+
+	token := &Token{Type: TokenTypeComment, Value: "// a"}
+	if diff := cmp.Diff(`0:0:0:0: COMMENT "// a"`, token.String()); diff != "" {
+		t.Errorf("Token.String() (-want +got):\n%s", diff)
+	}
+}
+
+func TestToken_String(t *testing.T) {
+	t.Parallel()
+
+	start := Position{Offset: 0, Line: 1, Column: 1}
+	end := Position{Offset: 4, Line: 1, Column: 5}
+
+	testCases := map[string]struct {
+		token *Token
+		want  string
+	}{
+		"builtin": {
+			token: &Token{Type: TokenTypeIdent, Value: "name", Start: start, End: end},
+			want:  `1:1:1:5: Ident "name"`,
+		},
+		"registered": {
+			token: &Token{Type: testTokenTypeName, Value: "name", Start: start, End: end},
+			want:  `1:1:1:5: NAME "name"`,
+		},
+		"rune": {
+			token: &Token{Type: '=', Value: "=", Start: start, End: start},
+			want:  `1:1:1:1: '='`,
+		},
+		"eof": {
+			token: &Token{Type: TokenTypeEOF, Start: end, End: end},
+			want:  `1:5:1:5: <EOF>`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.want, tc.token.String()); diff != "" {
+				t.Errorf("String() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -23,14 +23,129 @@ import (
 	"sync"
 )
 
-// channelBufSize is the size of the buffer for the token channel used between
-// the lexer and parser.
+// channelBufSize is the default size of the buffer for the token channel used
+// between the lexer and parser.
 const channelBufSize = 1024
 
+// LexParseOption is an option that configures [LexParse] and [LexParseSync].
+type LexParseOption func(*lexParseOptions)
+
+type lexParseOptions struct {
+	// tokenBufSize is the size of the buffer for the token channel.
+	tokenBufSize int
+
+	// errorJoin indicates that both the lexer and parser errors should be
+	// returned.
+	errorJoin bool
+
+	// tokenHook is called for each token read by the parser.
+	tokenHook func(*Token)
+
+	// filename is the name of the file being lexed.
+	filename string
+}
+
+// WithTokenBuffer sets the number of tokens that can be buffered between the
+// lexer and parser by [LexParse]. The default is 1024. If n is zero the lexer
+// and parser synchronize on every token. It has no effect on [LexParseSync].
+func WithTokenBuffer(n int) LexParseOption {
+	return func(o *lexParseOptions) {
+		o.tokenBufSize = max(n, 0)
+	}
+}
+
+// WithErrorJoin causes both the lexer and parser errors to be returned,
+// combined with [errors.Join]. By default, the lexer's error is returned if
+// there is one and the parser's error is returned otherwise.
+func WithErrorJoin() LexParseOption {
+	return func(o *lexParseOptions) {
+		o.errorJoin = true
+	}
+}
+
+// WithTokenHook sets a function that is called for each token as it is read by
+// the parser. The hook is always called on the parser's goroutine.
+func WithTokenHook(hook func(*Token)) LexParseOption {
+	return func(o *lexParseOptions) {
+		o.tokenHook = hook
+	}
+}
+
+// WithFilename sets the filename used in the positions of tokens and nodes.
+// The lexer must provide a SetFilename method, as [CustomLexer] and
+// [ScanningLexer] do, for the filename to be set on tokens.
+func WithFilename(name string) LexParseOption {
+	return func(o *lexParseOptions) {
+		o.filename = name
+	}
+}
+
+func newLexParseOptions(opts []LexParseOption) *lexParseOptions {
+	o := &lexParseOptions{
+		tokenBufSize: channelBufSize,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// filenameSetter is implemented by lexers that support setting the filename.
+type filenameSetter interface {
+	SetFilename(name string)
+}
+
+// newParser creates a new parser for the given options reading tokens from
+// tokens. The lexer is configured as well.
+func newParser[V comparable](
+	o *lexParseOptions,
+	lex Lexer,
+	tokens TokenSource,
+	startingState ParseState[V],
+) *Parser[V] {
+	if o.filename != "" {
+		if setter, ok := lex.(filenameSetter); ok {
+			setter.SetFilename(o.filename)
+		}
+	}
+
+	if o.tokenHook != nil {
+		tokens = &hookTokenSource{
+			src:  tokens,
+			hook: o.tokenHook,
+		}
+	}
+
+	p := NewParser(tokens, startingState)
+	p.root.Start.Filename = o.filename
+	p.root.End.Filename = o.filename
+
+	return p
+}
+
+// hookTokenSource implements the [TokenSource] interface by calling a hook for
+// each token read from another [TokenSource].
+type hookTokenSource struct {
+	src  TokenSource
+	hook func(*Token)
+}
+
+// NextToken implements [TokenSource.NextToken].
+func (s *hookTokenSource) NextToken(ctx context.Context) *Token {
+	token := s.src.NextToken(ctx)
+	s.hook(token)
+
+	return token
+}
+
 // tokenChan implements the [TokenSource] interface by reading tokens from
 // a channel.
 type tokenChan struct {
 	c chan *Token
+
+	// last is the last token read from the channel.
+	last *Token
 }
 
 // NextToken implements [TokenSource.NextToken].
@@ -40,16 +155,59 @@ func (tc *tokenChan) NextToken(_ context.Context) *Token {
 	//       Context is canceled. It is important to return the EOF token from
 	//       the lexer rather than return our own EOF token here to capture the
 	//       Position values of the EOF token.
-	return <-tc.c
+	token, ok := <-tc.c
+	if !ok {
+		// The lexer stopped before sending an EOF token. Return an EOF token
+		// at the end of the last token received.
+		var pos Position
+		if tc.last != nil {
+			pos = tc.last.End
+		}
+
+		return &Token{
+			Type:  TokenTypeEOF,
+			Start: pos,
+			End:   pos,
+		}
+	}
+
+	tc.last = token
+
+	return token
+}
+
+// sendTokens sends tokens from the lexer to c until an EOF token is sent or
+// ctx is done. It closes c before returning the lexer's error.
+func sendTokens(ctx context.Context, lex Lexer, c chan<- *Token) error {
+	defer close(c)
+
+	for {
+		token := lex.NextToken(ctx)
+
+		select {
+		case c <- token:
+		case <-ctx.Done():
+			// The parser has finished or the caller canceled the operation.
+			// Stop here rather than blocking on a full channel that is no
+			// longer being read.
+			return lex.Err()
+		}
+
+		if token.Type == TokenTypeEOF {
+			return lex.Err()
+		}
+	}
 }
 
 // LexParse lexes the content the given lexer and feeds the tokens concurrently
 // to the parser starting at startingState. The resulting root node of the parse
-// tree is returned.
+// tree is returned. LexParse can be configured by passing [LexParseOption]
+// values.
 func LexParse[V comparable](
 	ctx context.Context,
 	lex Lexer,
 	startingState ParseState[V],
+	opts ...LexParseOption,
 ) (*Node[V], error) {
 	var (
 		root     *Node[V]
@@ -58,24 +216,20 @@ func LexParse[V comparable](
 		waitGrp  sync.WaitGroup
 	)
 
+	o := newLexParseOptions(opts)
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	tokens := &tokenChan{
-		c: make(chan *Token, channelBufSize),
+		c: make(chan *Token, o.tokenBufSize),
 	}
 
-	p := NewParser(tokens, startingState)
+	p := newParser(o, lex, tokens, startingState)
 
 	waitGrp.Add(1)
 
 	go func() {
-		t := &Token{}
-		for t.Type != TokenTypeEOF {
-			t = lex.NextToken(ctx)
-			tokens.c <- t
-		}
-
-		lexErr = lex.Err()
+		lexErr = sendTokens(ctx, lex, tokens.c)
 
 		waitGrp.Done()
 	}()
@@ -91,12 +245,44 @@ func LexParse[V comparable](
 
 	waitGrp.Wait()
 
-	err := lexErr
+	return root, o.err(lexErr, parseErr)
+}
+
+// LexParseSync lexes and parses the content of the given lexer starting at
+// startingState without starting any goroutines. The parser pulls tokens
+// directly from the lexer on the calling goroutine as they are needed. The
+// resulting root node of the parse tree is returned. LexParseSync can be
+// configured by passing [LexParseOption] values.
+func LexParseSync[V comparable](
+	ctx context.Context,
+	lex Lexer,
+	startingState ParseState[V],
+	opts ...LexParseOption,
+) (*Node[V], error) {
+	o := newLexParseOptions(opts)
+
+	p := newParser(o, lex, lex, startingState)
+	root, parseErr := p.Parse(ctx)
+
+	return root, o.err(lex.Err(), parseErr)
+}
+
+// err returns the error to report given the errors returned by the lexer and
+// parser.
+func (o *lexParseOptions) err(lexErr, parseErr error) error {
 	// Do not report context.Canceled errors from the Lexer. If the context is
 	// canceled by the caller the parser will also return this error.
-	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, io.EOF) {
-		err = parseErr
+	if errors.Is(lexErr, context.Canceled) || errors.Is(lexErr, io.EOF) {
+		lexErr = nil
+	}
+
+	if o.errorJoin {
+		return errors.Join(lexErr, parseErr)
+	}
+
+	if lexErr == nil {
+		return parseErr
 	}
 
-	return root, err
+	return lexErr
 }
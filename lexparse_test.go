@@ -15,9 +15,11 @@
 package lexparse
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -63,6 +65,11 @@ func TestScannerLexParse(t *testing.T) {
 					Line:   1,
 					Column: 1,
 				},
+				End: Position{
+					Offset: 11,
+					Line:   2,
+					Column: 6,
+				},
 				Children: []*Node[string]{
 					{
 						Value: "Hello",
@@ -71,6 +78,11 @@ func TestScannerLexParse(t *testing.T) {
 							Line:   1,
 							Column: 1,
 						},
+						End: Position{
+							Offset: 5,
+							Line:   1,
+							Column: 6,
+						},
 					},
 					{
 						Value: "World",
@@ -79,6 +91,11 @@ func TestScannerLexParse(t *testing.T) {
 							Line:   2,
 							Column: 1,
 						},
+						End: Position{
+							Offset: 11,
+							Line:   2,
+							Column: 6,
+						},
 					},
 				},
 			},
@@ -149,6 +166,11 @@ func TestCustomLexParse(t *testing.T) {
 					Line:   1,
 					Column: 1,
 				},
+				End: Position{
+					Offset: 12,
+					Line:   2,
+					Column: 7,
+				},
 				Children: []*Node[string]{
 					{
 						Value: "Hello",
@@ -157,6 +179,11 @@ func TestCustomLexParse(t *testing.T) {
 							Line:   1,
 							Column: 1,
 						},
+						End: Position{
+							Offset: 5,
+							Line:   1,
+							Column: 6,
+						},
 					},
 					{
 						Value: "World!",
@@ -165,6 +192,11 @@ func TestCustomLexParse(t *testing.T) {
 							Line:   2,
 							Column: 1,
 						},
+						End: Position{
+							Offset: 12,
+							Line:   2,
+							Column: 7,
+						},
 					},
 				},
 			},
@@ -209,3 +241,294 @@ func TestCustomLexParse(t *testing.T) {
 		}
 	})
 }
+
+// parseOneState is a parser state that consumes a single token and stops.
+type parseOneState struct {
+	err error
+}
+
+func (s *parseOneState) Run(ctx *ParserContext[string]) error {
+	_ = ctx.Next()
+	return s.err
+}
+
+func TestLexParse_parserStopsEarly(t *testing.T) {
+	t.Parallel()
+
+	// The input produces many more tokens than can be buffered between the
+	// lexer and parser.
+	input := strings.Repeat("word ", 100*channelBufSize)
+
+	testCases := []struct {
+		name  string
+		lexer func() Lexer
+		err   error
+	}{
+		{
+			name: "custom lexer",
+			lexer: func() Lexer {
+				return NewCustomLexer(strings.NewReader(input), &lexWordState{})
+			},
+		},
+		{
+			name: "custom lexer parse error",
+			lexer: func() Lexer {
+				return NewCustomLexer(strings.NewReader(input), &lexWordState{})
+			},
+			err: errParse,
+		},
+		{
+			name: "scanning lexer",
+			lexer: func() Lexer {
+				return NewScanningLexer(strings.NewReader(input))
+			},
+		},
+		{
+			name: "scanning lexer parse error",
+			lexer: func() Lexer {
+				return NewScanningLexer(strings.NewReader(input))
+			},
+			err: errParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			done := make(chan error, 1)
+
+			go func() {
+				_, err := LexParse(t.Context(), tc.lexer(), &parseOneState{err: tc.err})
+				done <- err
+			}()
+
+			select {
+			case got := <-done:
+				if diff := cmp.Diff(tc.err, got, cmpopts.EquateErrors()); diff != "" {
+					t.Errorf("unexpected error (-want +got):\n%s", diff)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("LexParse did not return after the parser stopped")
+			}
+		})
+	}
+}
+
+func TestLexParseSync(t *testing.T) {
+	t.Parallel()
+
+	t.Run("basic", func(t *testing.T) {
+		t.Parallel()
+
+		r := strings.NewReader("Hello\nWorld!")
+
+		l := NewCustomLexer(r, &lexWordState{})
+
+		got, err := LexParseSync(t.Context(), l, &parseWordState{})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		expectedRoot := addParent(
+			&Node[string]{
+				Start: Position{
+					Offset: 0,
+					Line:   1,
+					Column: 1,
+				},
+				End: Position{
+					Offset: 12,
+					Line:   2,
+					Column: 7,
+				},
+				Children: []*Node[string]{
+					{
+						Value: "Hello",
+						Start: Position{
+							Offset: 0,
+							Line:   1,
+							Column: 1,
+						},
+						End: Position{
+							Offset: 5,
+							Line:   1,
+							Column: 6,
+						},
+					},
+					{
+						Value: "World!",
+						Start: Position{
+							Offset: 6,
+							Line:   2,
+							Column: 1,
+						},
+						End: Position{
+							Offset: 12,
+							Line:   2,
+							Column: 7,
+						},
+					},
+				},
+			},
+		)
+
+		if diff := cmp.Diff(expectedRoot, got); diff != "" {
+			t.Errorf("unexpected output (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("lexer error", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewCustomLexer(strings.NewReader("Hello\nWorld!"), &lexErrState{})
+		_, got := LexParseSync(t.Context(), l, &parseErrState{})
+
+		want := errState
+		if diff := cmp.Diff(want, got, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("unexpected error (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("parser error", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewCustomLexer(strings.NewReader("Hello\nWorld!"), &lexWordState{})
+		_, got := LexParseSync(t.Context(), l, &parseErrState{})
+
+		want := errParse
+		if diff := cmp.Diff(want, got, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("unexpected error (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestLexParse_options(t *testing.T) {
+	t.Parallel()
+
+	lexParseFuncs := map[string]func(
+		context.Context, Lexer, ParseState[string], ...LexParseOption,
+	) (*Node[string], error){
+		"LexParse":     LexParse[string],
+		"LexParseSync": LexParseSync[string],
+	}
+
+	for name, lexParse := range lexParseFuncs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("WithTokenBuffer", func(t *testing.T) {
+				t.Parallel()
+
+				l := NewCustomLexer(strings.NewReader("Hello\nWorld!"), &lexWordState{})
+
+				got, err := lexParse(t.Context(), l, &parseWordState{}, WithTokenBuffer(0))
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+
+				if diff := cmp.Diff(2, len(got.Children)); diff != "" {
+					t.Errorf("len(Children) (-want +got):\n%s", diff)
+				}
+			})
+
+			t.Run("WithErrorJoin", func(t *testing.T) {
+				t.Parallel()
+
+				l := NewCustomLexer(strings.NewReader("Hello\nWorld!"), &lexErrState{})
+
+				_, got := lexParse(t.Context(), l, &parseErrState{}, WithErrorJoin())
+				if !errors.Is(got, errState) {
+					t.Errorf("expected error %v, got %v", errState, got)
+				}
+
+				if !errors.Is(got, errParse) {
+					t.Errorf("expected error %v, got %v", errParse, got)
+				}
+			})
+
+			t.Run("WithTokenHook", func(t *testing.T) {
+				t.Parallel()
+
+				l := NewCustomLexer(strings.NewReader("Hello\nWorld!"), &lexWordState{})
+
+				var values []string
+
+				_, err := lexParse(t.Context(), l, &parseWordState{}, WithTokenHook(func(token *Token) {
+					values = append(values, token.Value)
+				}))
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+
+				if diff := cmp.Diff([]string{"Hello", "World!", ""}, values); diff != "" {
+					t.Errorf("tokens (-want +got):\n%s", diff)
+				}
+			})
+
+			t.Run("WithFilename", func(t *testing.T) {
+				t.Parallel()
+
+				l := NewCustomLexer(strings.NewReader("Hello\nWorld!"), &lexWordState{})
+
+				got, err := lexParse(t.Context(), l, &parseWordState{}, WithFilename("hello.txt"))
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+
+				expectedRoot := addParent(
+					&Node[string]{
+						Start: Position{
+							Filename: "hello.txt",
+							Offset:   0,
+							Line:     1,
+							Column:   1,
+						},
+						End: Position{
+							Filename: "hello.txt",
+							Offset:   12,
+							Line:     2,
+							Column:   7,
+						},
+						Children: []*Node[string]{
+							{
+								Value: "Hello",
+								Start: Position{
+									Filename: "hello.txt",
+									Offset:   0,
+									Line:     1,
+									Column:   1,
+								},
+								End: Position{
+									Filename: "hello.txt",
+									Offset:   5,
+									Line:     1,
+									Column:   6,
+								},
+							},
+							{
+								Value: "World!",
+								Start: Position{
+									Filename: "hello.txt",
+									Offset:   6,
+									Line:     2,
+									Column:   1,
+								},
+								End: Position{
+									Filename: "hello.txt",
+									Offset:   12,
+									Line:     2,
+									Column:   7,
+								},
+							},
+						},
+					},
+				)
+
+				if diff := cmp.Diff(expectedRoot, got); diff != "" {
+					t.Errorf("unexpected output (-want +got):\n%s", diff)
+				}
+			})
+		})
+	}
+}
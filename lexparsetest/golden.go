@@ -0,0 +1,113 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lexparsetest provides helpers for testing parsers written with
+// lexparse.
+//
+// Golden file helpers compare output with the contents of a file. Golden files
+// are updated with the output instead when tests are run with the
+// -lexparse.update flag:
+//
+//	go test ./... -args -lexparse.update
+package lexparsetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/lexparse"
+)
+
+//nolint:gochecknoglobals // The flag is registered when the package is imported by tests.
+var update = flag.Bool("lexparse.update", false, "update golden files")
+
+// AssertGolden reports a test error if got is not equal to the contents of the
+// golden file at path. If the -lexparse.update flag is set, the golden file is
+// written with got instead.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("creating golden file directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist; run tests with -lexparse.update to create it", path)
+	}
+
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("%s (-want +got):\n%s", path, cmp.Diff(string(want), string(got)))
+	}
+}
+
+// AssertTree reports a test error if the S-expression of the tree rooted at
+// root written by [lexparse.WriteSExpr] with label is not equal to the
+// contents of the golden file at path.
+func AssertTree[V comparable](t testing.TB, path string, root *lexparse.Node[V], label func(V) string) {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := lexparse.WriteSExpr(&b, root, label); err != nil {
+		t.Fatalf("writing tree: %v", err)
+	}
+
+	AssertGolden(t, path, b.Bytes())
+}
+
+// AssertTreeJSON reports a test error if the indented JSON encoding of the
+// tree rooted at root by [lexparse.MarshalTreeJSON] with encode is not equal
+// to the contents of the golden file at path. JSON golden files include the
+// positions of each node.
+func AssertTreeJSON[V comparable](
+	t testing.TB,
+	path string,
+	root *lexparse.Node[V],
+	encode func(V) (any, error),
+) {
+	t.Helper()
+
+	data, err := lexparse.MarshalTreeJSON(root, encode)
+	if err != nil {
+		t.Fatalf("encoding tree: %v", err)
+	}
+
+	var b bytes.Buffer
+	if err := json.Indent(&b, data, "", "  "); err != nil {
+		t.Fatalf("indenting JSON: %v", err)
+	}
+
+	b.WriteString("\n")
+
+	AssertGolden(t, path, b.Bytes())
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparsetest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ianlewis/lexparse"
+)
+
+// parseExpr parses an arithmetic expression.
+func parseExpr(t *testing.T, input string) *lexparse.Node[string] {
+	t.Helper()
+
+	p := lexparse.NewPratt[string]()
+
+	value := func(token *lexparse.Token) string {
+		return token.Value
+	}
+
+	p.Literal(lexparse.TokenTypeInt, func(token *lexparse.Token) (string, error) {
+		return token.Value, nil
+	})
+	p.Binary('+', 1, lexparse.LeftAssoc, value)
+	p.Binary('*', 2, lexparse.LeftAssoc, value)
+
+	root, err := lexparse.LexParseSync(
+		context.Background(),
+		lexparse.NewScanningLexer(strings.NewReader(input)),
+		lexparse.ParseState[string](p),
+	)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", input, err)
+	}
+
+	return root
+}
+
+// fakeTB records errors reported by test helpers.
+type fakeTB struct {
+	testing.TB
+
+	errors []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertTree(t *testing.T) {
+	t.Parallel()
+
+	root := parseExpr(t, "1 + 2 * 3")
+
+	AssertTree(t, filepath.Join("testdata", "expr.sexpr"), root, nil)
+}
+
+func TestAssertTreeJSON(t *testing.T) {
+	t.Parallel()
+
+	root := parseExpr(t, "1 + 2 * 3")
+
+	AssertTreeJSON(t, filepath.Join("testdata", "expr.json"), root, nil)
+}
+
+func TestAssertTree_mismatch(t *testing.T) {
+	t.Parallel()
+
+	if *update {
+		t.Skip("golden files are being updated")
+	}
+
+	root := parseExpr(t, "1 * 2 + 3")
+
+	tb := &fakeTB{TB: t}
+	AssertTree(tb, filepath.Join("testdata", "expr.sexpr"), root, nil)
+
+	if len(tb.errors) != 1 {
+		t.Fatalf("AssertTree: want 1 error, got %d", len(tb.errors))
+	}
+
+	if !strings.Contains(tb.errors[0], "expr.sexpr (-want +got)") {
+		t.Errorf("AssertTree: unexpected error: %s", tb.errors[0])
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"slices"
+)
+
+// memoState is a [ParseState] that caches the results of another state.
+type memoState[V comparable] struct {
+	state ParseState[V]
+}
+
+// memoKey is the key for a cached result of a [memoState].
+type memoKey[V comparable] struct {
+	state *memoState[V]
+
+	// index is the token index where the state was run.
+	index int
+}
+
+// memoEntry is the cached result of running a [memoState].
+type memoEntry[V comparable] struct {
+	// children are the nodes added to the current node.
+	children []*Node[V]
+
+	// advance is the number of tokens consumed.
+	advance int
+
+	// states are the states pushed onto the state stack.
+	states []ParseState[V]
+
+	// errs are the errors reported.
+	errs []error
+
+	// err is the error returned.
+	err error
+}
+
+// Memo returns a [ParseState] that caches the result of running state at each
+// token index. When the returned state is run again at the same token index,
+// for example after [ParserContext.Reset], the cached result is replayed
+// rather than running state again. The cached result includes the nodes added
+// to the current node, the tokens consumed, the states pushed onto the state
+// stack, reported errors, and the returned error. This gives linear time
+// packrat parsing for grammars that try alternatives.
+//
+// Results are only cached if state does not change the current node, for
+// example by calling [ParserContext.Push] without a matching
+// [ParserContext.Climb], or otherwise modify the existing parse tree. State
+// must not depend on anything other than the upcoming tokens.
+//
+// The cache is keyed by the returned state so Memo should be called once for
+// each state and the result reused.
+//
+//nolint:ireturn // returning the generic interface is needed to wrap any ParseState.
+func Memo[V comparable](state ParseState[V]) ParseState[V] {
+	return &memoState[V]{state: state}
+}
+
+// Run implements [ParseState.Run].
+func (s *memoState[V]) Run(ctx *ParserContext[V]) error {
+	p := ctx.p
+
+	key := memoKey[V]{
+		state: s,
+		index: p.index,
+	}
+
+	if entry, ok := p.memo[key]; ok {
+		return p.replay(ctx, entry)
+	}
+
+	node := p.node
+	root := p.root
+	children := node.Children
+	index := p.index
+	states := len(*p.stateStack)
+	errs := len(p.errs)
+
+	err := s.state.Run(ctx)
+
+	// Only cache results that can be replayed by appending children to the
+	// current node.
+	if p.node != node || p.root != root ||
+		len(node.Children) < len(children) ||
+		!slices.Equal(node.Children[:len(children)], children) ||
+		len(*p.stateStack) < states || len(p.errs) < errs {
+		return err
+	}
+
+	entry := &memoEntry[V]{
+		advance: p.index - index,
+		states:  slices.Clone((*p.stateStack)[states:]),
+		errs:    slices.Clone(p.errs[errs:]),
+		err:     err,
+	}
+
+	for _, child := range node.Children[len(children):] {
+		entry.children = append(entry.children, cloneTree(child, nil))
+	}
+
+	if p.memo == nil {
+		p.memo = map[memoKey[V]]*memoEntry[V]{}
+	}
+
+	p.memo[key] = entry
+
+	return err
+}
+
+// replay applies a cached result to the parser.
+func (p *Parser[V]) replay(ctx *ParserContext[V], entry *memoEntry[V]) error {
+	for range entry.advance {
+		_ = p.nextToken(ctx)
+	}
+
+	for _, child := range entry.children {
+		p.appendNode(cloneTree(child, nil))
+	}
+
+	*p.stateStack = append(*p.stateStack, entry.states...)
+	p.errs = append(p.errs, entry.errs...)
+
+	return entry.err
+}
+
+// cloneTree returns a deep copy of the tree rooted at n with the given parent.
+func cloneTree[V comparable](n, parent *Node[V]) *Node[V] {
+	c := &Node[V]{
+		Parent: parent,
+		Value:  n.Value,
+		Start:  n.Start,
+		End:    n.End,
+	}
+
+	if n.Children != nil {
+		c.Children = make([]*Node[V], 0, len(n.Children))
+		for _, child := range n.Children {
+			c.Children = append(c.Children, cloneTree(child, c))
+		}
+	}
+
+	return c
+}
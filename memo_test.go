@@ -0,0 +1,238 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMemo(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext("A B C")
+
+	var runs int
+
+	// Parse two words as a pair node.
+	pair := Memo(ParseStateFn(func(ctx *ParserContext[string]) error {
+		runs++
+
+		_ = ctx.Push("pair")
+		_ = ctx.Node(ctx.Next().Value)
+		_ = ctx.Node(ctx.Next().Value)
+		_ = ctx.Climb()
+
+		ctx.PushState(&parseWordState{})
+		ctx.Report(errParse)
+
+		return nil
+	}))
+
+	m := ctx.Mark()
+
+	if err := pair.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantTree := ctx.Root().Clone()
+	wantStack := len(*ctx.p.stateStack)
+	wantIndex := ctx.p.index
+
+	ctx.Reset(m)
+
+	if err := pair.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx.Commit(m)
+
+	if diff := cmp.Diff(1, runs); diff != "" {
+		t.Errorf("runs (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(wantTree, ctx.Root()); diff != "" {
+		t.Errorf("Root (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(wantStack, len(*ctx.p.stateStack)); diff != "" {
+		t.Errorf("state stack (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(wantIndex, ctx.p.index); diff != "" {
+		t.Errorf("index (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(1, len(ctx.p.errs)); diff != "" {
+		t.Errorf("errors (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff("C", ctx.Next().Value); diff != "" {
+		t.Errorf("Next (-want +got):\n%s", diff)
+	}
+
+	// The cached result is not used at a different token index.
+	if err := pair.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(2, runs); diff != "" {
+		t.Errorf("runs (-want +got):\n%s", diff)
+	}
+}
+
+func TestMemo_error(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext("A B")
+
+	var runs int
+
+	word := Memo(ParseStateFn(func(ctx *ParserContext[string]) error {
+		runs++
+
+		return NewTokenError(ctx.Next(), errParse)
+	}))
+
+	m := ctx.Mark()
+
+	err1 := word.Run(ctx)
+	ctx.Reset(m)
+	err2 := word.Run(ctx)
+
+	if diff := cmp.Diff(1, runs); diff != "" {
+		t.Errorf("runs (-want +got):\n%s", diff)
+	}
+
+	if !errors.Is(err2, errParse) || err1 != err2 { //nolint:errorlint // checking the error is cached.
+		t.Errorf("Run: want %v, got %v", err1, err2)
+	}
+
+	if diff := cmp.Diff("B", ctx.Peek().Value); diff != "" {
+		t.Errorf("Peek (-want +got):\n%s", diff)
+	}
+}
+
+func TestMemo_push(t *testing.T) {
+	t.Parallel()
+
+	ctx := newTestContext("A B")
+
+	var runs int
+
+	// The state changes the current node so the result can't be cached.
+	open := Memo(ParseStateFn(func(ctx *ParserContext[string]) error {
+		runs++
+
+		_ = ctx.Push(ctx.Next().Value)
+
+		return nil
+	}))
+
+	m := ctx.Mark()
+
+	_ = open.Run(ctx)
+
+	ctx.Reset(m)
+
+	_ = open.Run(ctx)
+
+	if diff := cmp.Diff(2, runs); diff != "" {
+		t.Errorf("runs (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff("A", ctx.Pos().Value); diff != "" {
+		t.Errorf("Pos (-want +got):\n%s", diff)
+	}
+}
+
+func TestMemo_parse(t *testing.T) {
+	t.Parallel()
+
+	// The grammar tries to parse "x x x ... y" and falls back to "x x x ... z".
+	// Without memoization the list of x is parsed once for each alternative
+	// at each position.
+	var runs int
+
+	var xs ParseState[string]
+
+	xs = Memo(ParseStateFn(func(ctx *ParserContext[string]) error {
+		runs++
+
+		if ctx.Peek().Value != "x" {
+			return nil
+		}
+
+		_ = ctx.Node(ctx.Next().Value)
+
+		return xs.Run(ctx)
+	}))
+
+	parseEnd := func(ctx *ParserContext[string], end string) bool {
+		m := ctx.Mark()
+
+		_ = ctx.Push(end)
+		_ = xs.Run(ctx)
+
+		if ctx.Next().Value == end {
+			_ = ctx.Climb()
+			ctx.Commit(m)
+
+			return true
+		}
+
+		ctx.Reset(m)
+		ctx.Commit(m)
+
+		return false
+	}
+
+	parse := ParseStateFn(func(ctx *ParserContext[string]) error {
+		if !parseEnd(ctx, "y") && !parseEnd(ctx, "z") {
+			return errParse
+		}
+
+		return nil
+	})
+
+	l := NewCustomLexer(strings.NewReader("x x x x z"), &lexWordState{})
+
+	root, err := NewParser(l, parse).Parse(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Four x and the end of the list.
+	if diff := cmp.Diff(5, runs); diff != "" {
+		t.Errorf("runs (-want +got):\n%s", diff)
+	}
+
+	var values []string
+	for _, child := range root.Children[0].Children {
+		values = append(values, child.Value)
+	}
+
+	if diff := cmp.Diff("z", root.Children[0].Value); diff != "" {
+		t.Errorf("Value (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"x", "x", "x", "x"}, values); diff != "" {
+		t.Errorf("Children (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"unicode/utf8"
+)
+
+// WithOperators registers operators that are returned by the lexer as a single
+// token of the given [TokenType] rather than as a token for each rune. For
+// example,
+//
+//	lexparse.WithOperators(map[string]lexparse.TokenType{
+//		"==": tokenTypeEq,
+//		"<=": tokenTypeLe,
+//		"&&": tokenTypeAnd,
+//	})
+//
+// Operators are matched with longest-match semantics so that "<=" is matched
+// rather than "<" if both are registered. Operators must be made up of
+// characters that the scanner returns as single rune tokens, such as
+// punctuation, and may not contain whitespace. Operators from multiple
+// WithOperators options are combined.
+//
+// Token types for operators should be outside the printable ASCII range unless
+// a name is registered with [RegisterTokenType]. Otherwise, they are shown as
+// characters in token dumps and error messages. See [TokenType].
+func WithOperators(operators map[string]TokenType) ScanningLexerOption {
+	return func(o *scanningLexerOptions) {
+		if o.operators == nil {
+			o.operators = map[string]TokenType{}
+		}
+
+		for op, typ := range operators {
+			if op != "" {
+				o.operators[op] = typ
+			}
+		}
+	}
+}
+
+// operatorPrefixes returns the set of non-empty prefixes of the operators,
+// including the operators themselves.
+func operatorPrefixes(operators map[string]TokenType) map[string]bool {
+	prefixes := map[string]bool{}
+
+	for op := range operators {
+		for i := range op {
+			if i > 0 {
+				prefixes[op[:i]] = true
+			}
+		}
+
+		prefixes[op] = true
+	}
+
+	return prefixes
+}
+
+// matchOperator extends a single rune token to the longest operator that
+// begins with it. The input following the token is examined without being
+// consumed so that input that is not part of the operator is scanned as usual.
+func (l *ScanningLexer) matchOperator(token *Token) *Token {
+	text := token.Value
+	if !l.operatorPrefixes[text] {
+		return token
+	}
+
+	typ, matchedText, matched := token.Type, text, 0
+	if t, ok := l.operators[text]; ok {
+		typ = t
+	}
+
+	// NOTE: The position of the lexer is at the end of the token so the
+	// recorded input begins with the input following it.
+	var offset, runes int
+
+	for {
+		ahead := l.src.peek(offset + utf8.UTFMax)
+		if offset >= len(ahead) {
+			break
+		}
+
+		_, size := utf8.DecodeRune(ahead[offset:])
+		text += string(ahead[offset : offset+size])
+		offset += size
+		runes++
+
+		if !l.operatorPrefixes[text] {
+			break
+		}
+
+		if t, ok := l.operators[text]; ok {
+			typ, matchedText, matched = t, text, runes
+		}
+	}
+
+	token.Type = typ
+
+	if matched > 0 {
+		for range matched {
+			l.s.Next()
+		}
+
+		token.Value = matchedText
+		token.End = l.position(l.s.Pos())
+	}
+
+	return token
+}
@@ -0,0 +1,316 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	opTypeEq TokenType = iota + 2000
+	opTypeNe
+	opTypeLe
+	opTypeShl
+	opTypeShlAssign
+	opTypeAnd
+	opTypeArrow
+	opTypeDefine
+	opTypeEllipsis
+	opTypeNotEq
+	opTypeLt
+)
+
+// testOperators returns the operators used in tests.
+func testOperators() map[string]TokenType {
+	return map[string]TokenType{
+		"==":  opTypeEq,
+		"!=":  opTypeNe,
+		"<=":  opTypeLe,
+		"<<":  opTypeShl,
+		"<<=": opTypeShlAssign,
+		"&&":  opTypeAnd,
+		"->":  opTypeArrow,
+		":=":  opTypeDefine,
+		"...": opTypeEllipsis,
+		"=!=": opTypeNotEq,
+		"<":   opTypeLt,
+	}
+}
+
+func TestWithOperators(t *testing.T) {
+	t.Parallel()
+
+	type token struct {
+		Type  TokenType
+		Value string
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []token
+	}{
+		{
+			name:  "operators",
+			input: "a := b == c && d != e -> f(g...)",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{opTypeDefine, ":="},
+				{TokenTypeIdent, "b"},
+				{opTypeEq, "=="},
+				{TokenTypeIdent, "c"},
+				{opTypeAnd, "&&"},
+				{TokenTypeIdent, "d"},
+				{opTypeNe, "!="},
+				{TokenTypeIdent, "e"},
+				{opTypeArrow, "->"},
+				{TokenTypeIdent, "f"},
+				{'(', "("},
+				{TokenTypeIdent, "g"},
+				{opTypeEllipsis, "..."},
+				{')', ")"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "longest match",
+			input: "a<b<=c<<d<<=e",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{opTypeLt, "<"},
+				{TokenTypeIdent, "b"},
+				{opTypeLe, "<="},
+				{TokenTypeIdent, "c"},
+				{opTypeShl, "<<"},
+				{TokenTypeIdent, "d"},
+				{opTypeShlAssign, "<<="},
+				{TokenTypeIdent, "e"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "whitespace",
+			input: "a = = b & & c",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{'=', "="},
+				{'=', "="},
+				{TokenTypeIdent, "b"},
+				{'&', "&"},
+				{'&', "&"},
+				{TokenTypeIdent, "c"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "partial match",
+			input: "a..b",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{'.', "."},
+				{'.', "."},
+				{TokenTypeIdent, "b"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "partial match before number",
+			input: "a..5",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{'.', "."},
+				{TokenTypeFloat, ".5"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "partial match before comment",
+			input: "a.// b\n.",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{'.', "."},
+				{TokenTypeComment, "// b"},
+				{'.', "."},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "match before number",
+			input: "a...5",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{opTypeEllipsis, "..."},
+				{TokenTypeInt, "5"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "partial match before operator",
+			input: "a =!!= b =!= c",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{'=', "="},
+				{'!', "!"},
+				{opTypeNe, "!="},
+				{TokenTypeIdent, "b"},
+				{opTypeNotEq, "=!="},
+				{TokenTypeIdent, "c"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "partial match at EOF",
+			input: "a <<",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{opTypeShl, "<<"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "partial match at EOF with single rune",
+			input: "a..",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{'.', "."},
+				{'.', "."},
+				{TokenTypeEOF, ""},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := NewScanningLexer(strings.NewReader(tc.input), WithOperators(testOperators()))
+
+			var got []token
+
+			for {
+				tok := l.NextToken(context.Background())
+				got = append(got, token{tok.Type, tok.Value})
+
+				if tok.Type == TokenTypeEOF {
+					break
+				}
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected tokens (-want +got):\n%s", diff)
+			}
+
+			if err := l.Err(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithOperators_position(t *testing.T) {
+	t.Parallel()
+
+	l := NewScanningLexer(strings.NewReader("a =!!= b"), WithOperators(testOperators()))
+
+	var got []*Token
+
+	for {
+		tok := l.NextToken(context.Background())
+		got = append(got, tok)
+
+		if tok.Type == TokenTypeEOF {
+			break
+		}
+	}
+
+	expected := []*Token{
+		{
+			Type:  TokenTypeIdent,
+			Value: "a",
+			Start: Position{Offset: 0, Line: 1, Column: 1},
+			End:   Position{Offset: 1, Line: 1, Column: 2},
+		},
+		{
+			Type:  '=',
+			Value: "=",
+			Start: Position{Offset: 2, Line: 1, Column: 3},
+			End:   Position{Offset: 3, Line: 1, Column: 4},
+		},
+		{
+			Type:  '!',
+			Value: "!",
+			Start: Position{Offset: 3, Line: 1, Column: 4},
+			End:   Position{Offset: 4, Line: 1, Column: 5},
+		},
+		{
+			Type:  opTypeNe,
+			Value: "!=",
+			Start: Position{Offset: 4, Line: 1, Column: 5},
+			End:   Position{Offset: 6, Line: 1, Column: 7},
+		},
+		{
+			Type:  TokenTypeIdent,
+			Value: "b",
+			Start: Position{Offset: 7, Line: 1, Column: 8},
+			End:   Position{Offset: 8, Line: 1, Column: 9},
+		},
+		{
+			Type:  TokenTypeEOF,
+			Start: Position{Offset: 8, Line: 1, Column: 9},
+			End:   Position{Offset: 8, Line: 1, Column: 9},
+		},
+	}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected tokens (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithOperators_readAhead(t *testing.T) {
+	t.Parallel()
+
+	// Read one byte at a time so that operators are matched past the input
+	// read by the scanner.
+	l := NewScanningLexer(
+		iotest.OneByteReader(strings.NewReader("a<<=b..5")),
+		WithOperators(testOperators()),
+	)
+
+	var got []string
+
+	for {
+		tok := l.NextToken(context.Background())
+		if tok.Type == TokenTypeEOF {
+			break
+		}
+
+		got = append(got, tok.Value)
+	}
+
+	expected := []string{"a", "<<=", "b", ".", ".5"}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected tokens (-want +got):\n%s", diff)
+	}
+
+	if err := l.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -17,8 +17,8 @@ package lexparse
 import (
 	"context"
 	"errors"
-	"fmt"
 	"io"
+	"slices"
 	"strings"
 )
 
@@ -30,41 +30,29 @@ type Node[V comparable] struct {
 
 	// Start is the start position in the input where the value was found.
 	Start Position
-}
 
-func (n *Node[V]) String() string {
-	return fmtNode(n, nil)
+	// End is the end position in the input of the last token consumed while
+	// the node was open. It is extended as needed to cover the end positions
+	// of the node's children.
+	End Position
 }
 
-func fmtNode[V comparable](node *Node[V], lastRank []bool) string {
-	var bldr strings.Builder
-
-	for i := range len(lastRank) - 1 {
-		if lastRank[i] {
-			bldr.WriteString("    ")
-		} else {
-			bldr.WriteString("│   ")
-		}
-	}
-
-	if len(lastRank) > 0 {
-		if lastRank[len(lastRank)-1] {
-			bldr.WriteString("└── ")
-		} else {
-			bldr.WriteString("├── ")
-		}
+// Span returns the span of the input covered by the node and its children.
+func (n *Node[V]) Span() Span {
+	return Span{
+		Start: n.Start,
+		End:   n.End,
 	}
+}
 
-	fmt.Fprintf(&bldr, "%v (%v)\n", node.Value, node.Start)
+// String returns the tree rooted at n printed by a zero value [TreePrinter].
+func (n *Node[V]) String() string {
+	var b strings.Builder
 
-	for i, child := range node.Children {
-		newLastRank := make([]bool, len(lastRank)+1)
-		copy(newLastRank, lastRank)
-		newLastRank[len(lastRank)] = i == len(node.Children)-1
-		bldr.WriteString(fmtNode(child, newLastRank))
-	}
+	// Writing to a strings.Builder does not fail.
+	_ = (&TreePrinter[V]{}).Fprint(&b, n)
 
-	return bldr.String()
+	return b.String()
 }
 
 // ParseState is the state of the current parsing state machine. It defines the
@@ -112,6 +100,68 @@ func (s *stack[V]) pop() ParseState[V] {
 	return v
 }
 
+// tokenRing is a ring buffer of tokens used as a FIFO queue.
+type tokenRing struct {
+	buf  []*Token
+	head int
+	n    int
+}
+
+// len returns the number of tokens in the ring.
+func (r *tokenRing) len() int {
+	return r.n
+}
+
+// at returns the i-th token from the front of the ring.
+func (r *tokenRing) at(i int) *Token {
+	return r.buf[(r.head+i)%len(r.buf)]
+}
+
+// push adds a token to the back of the ring.
+func (r *tokenRing) push(token *Token) {
+	r.grow()
+	r.buf[(r.head+r.n)%len(r.buf)] = token
+	r.n++
+}
+
+// pushFront adds a token to the front of the ring.
+func (r *tokenRing) pushFront(token *Token) {
+	r.grow()
+	r.head = (r.head - 1 + len(r.buf)) % len(r.buf)
+	r.buf[r.head] = token
+	r.n++
+}
+
+// grow grows the ring if it is full.
+func (r *tokenRing) grow() {
+	if r.n < len(r.buf) {
+		return
+	}
+
+	buf := make([]*Token, max(2*len(r.buf), 1))
+	for i := range r.n {
+		buf[i] = r.at(i)
+	}
+
+	r.buf = buf
+	r.head = 0
+}
+
+// pop removes and returns the token at the front of the ring. It returns nil
+// if the ring is empty.
+func (r *tokenRing) pop() *Token {
+	if r.n == 0 {
+		return nil
+	}
+
+	token := r.buf[r.head]
+	r.buf[r.head] = nil
+	r.head = (r.head + 1) % len(r.buf)
+	r.n--
+
+	return token
+}
+
 // TokenSource is an interface that defines a source of tokens for the parser.
 type TokenSource interface {
 	// NextToken returns the next token from the source. When tokens are
@@ -152,12 +202,35 @@ func (ctx *ParserContext[V]) Peek() *Token {
 	return ctx.p.peek(ctx)
 }
 
+// PeekN returns the k-th upcoming token from the lexer without consuming any
+// tokens. PeekN(1) is equivalent to Peek. Values of k less than 1 are treated
+// as 1. If there are fewer than k tokens remaining, the EOF token is returned.
+func (ctx *ParserContext[V]) PeekN(k int) *Token {
+	return ctx.p.peekN(ctx, k)
+}
+
 // Next returns the next token from the lexer. This is the new current token
 // position.
 func (ctx *ParserContext[V]) Next() *Token {
 	return ctx.p.nextToken(ctx)
 }
 
+// Report records an error and allows parsing to continue. The error is
+// wrapped as with errors returned by a [ParseState]. When parsing finishes,
+// [Parser.Parse] returns the partial parse tree and an [ErrorList] of all
+// reported errors. Report is typically used with [ParserContext.SyncTo] to
+// recover from an error.
+func (ctx *ParserContext[V]) Report(err error) {
+	ctx.p.report(err)
+}
+
+// SyncTo skips tokens until the next token has one of the given types or is
+// an EOF token, and returns it without consuming it. It is used to recover from
+// errors by skipping to a known synchronization point in the input.
+func (ctx *ParserContext[V]) SyncTo(types ...TokenType) *Token {
+	return ctx.p.syncTo(ctx, types)
+}
+
 // Pos returns the current node position in the tree.
 func (ctx *ParserContext[V]) Pos() *Node[V] {
 	return ctx.p.node
@@ -181,17 +254,26 @@ func (ctx *ParserContext[V]) NewNode(v V) *Node[V] {
 	return ctx.p.newNode(v)
 }
 
-// Climb updates the current node position to the current node's parent
-// returning the previous current node. It is a no-op that returns the root
-// node if called on the root node. Updates the end position of the parent node
-// to the end position of the current node.
+// Climb closes the current node and updates the current node position to the
+// current node's parent returning the previous current node. It is a no-op
+// that returns the root node if called on the root node.
+//
+// Closing a node sets its end position to the end of the last consumed token
+// and extends the end positions of its ancestors to cover it.
 func (ctx *ParserContext[V]) Climb() *Node[V] {
 	return ctx.p.climb()
 }
 
+// SetEnd closes the given node, setting its end position to the end of the
+// last consumed token and extending the end positions of its ancestors to
+// cover it. This is useful for nodes created with [ParserContext.NewNode].
+func (ctx *ParserContext[V]) SetEnd(n *Node[V]) {
+	ctx.p.closeNode(n)
+}
+
 // Replace replaces the current node with a new node with the given value. The
-// old node is removed from the tree and its value is returned. Can be used to
-// replace the root node.
+// old node is removed from the tree and its value is returned. The new node is
+// closed as with [ParserContext.Climb]. Can be used to replace the root node.
 //
 //nolint:ireturn // returning the generic interface is needed to return the previous value.
 func (ctx *ParserContext[V]) Replace(v V) V {
@@ -207,6 +289,11 @@ func NewParser[V comparable](tokens TokenSource, startingState ParseState[V]) *P
 			Line:   1,
 			Column: 1,
 		},
+		End: Position{
+			Offset: 0,
+			Line:   1,
+			Column: 1,
+		},
 	}
 	p := &Parser[V]{
 		stateStack: &stack[V]{},
@@ -242,8 +329,36 @@ type Parser[V comparable] struct {
 	// token is the current token in the stream.
 	token *Token
 
-	// next is the next token in the stream.
-	next *Token
+	// lookahead holds the upcoming tokens that have been read from tokens but
+	// not yet consumed.
+	lookahead tokenRing
+
+	// index is the number of tokens consumed.
+	index int
+
+	// end is the end position of the last consumed token that was not an EOF
+	// token.
+	end Position
+
+	// errs are the errors reported while parsing.
+	errs ErrorList
+
+	// marks are the active checkpoints created by [ParserContext.Mark] in the
+	// order they were created.
+	marks []checkpoint[V]
+
+	// markID is the ID of the last checkpoint created.
+	markID int
+
+	// consumed holds the tokens consumed while there are active marks.
+	consumed []*Token
+
+	// undo holds functions that undo changes to the parse tree made while
+	// there are active marks.
+	undo []func()
+
+	// memo holds the cached results of states created by [Memo].
+	memo map[memoKey[V]]*memoEntry[V]
 }
 
 // Parse builds a parse tree by repeatedly pulling [ParseState] objects from
@@ -251,6 +366,12 @@ type Parser[V comparable] struct {
 // canceled by ctx.
 //
 // The caller can request that the parser stop by canceling ctx.
+//
+// Errors returned by parser states are returned as an [*Error] at the span of
+// the current token unless they already wrap an [*Error] or were caused by ctx
+// being done. If errors were reported with [ParserContext.Report], they are
+// returned as an [ErrorList] along with any error returned by a parser state
+// or the error of ctx. The partial parse tree is returned along with errors.
 func (p *Parser[V]) Parse(ctx context.Context) (*Node[V], error) {
 	parserCtx := &ParserContext[V]{
 		Context: ctx,
@@ -265,12 +386,16 @@ func (p *Parser[V]) Parse(ctx context.Context) (*Node[V], error) {
 
 		select {
 		case <-ctx.Done():
-			if err := ctx.Err(); err != nil {
-				//nolint:wrapcheck // no additional error context for error.
-				return p.root, err
+			// Close any nodes that are still open so that the partial tree
+			// has valid spans.
+			p.closeNode(p.node)
+
+			if len(p.errs) > 0 {
+				return p.root, errors.Join(p.errs, ctx.Err())
 			}
 
-			return p.root, nil
+			//nolint:wrapcheck // no additional error context for error.
+			return p.root, ctx.Err()
 		default:
 		}
 
@@ -280,14 +405,56 @@ func (p *Parser[V]) Parse(ctx context.Context) (*Node[V], error) {
 				break
 			}
 
-			//nolint:wrapcheck // no additional error context for error.
-			return p.root, err
+			// Close any nodes that are still open so that the partial tree
+			// has valid spans.
+			p.closeNode(p.node)
+
+			if len(p.errs) > 0 {
+				p.report(err)
+				return p.root, p.errs
+			}
+
+			return p.root, p.wrapError(err)
 		}
 	}
 
+	// Close any nodes that are still open.
+	p.closeNode(p.node)
+
+	if len(p.errs) > 0 {
+		return p.root, p.errs
+	}
+
 	return p.root, nil
 }
 
+// wrapError returns err as an [*Error] at the span of the current token.
+func (p *Parser[V]) wrapError(err error) error {
+	var span Span
+	if p.token != nil {
+		span = p.token.Span()
+	}
+
+	return wrapError(span, err)
+}
+
+func (p *Parser[V]) report(err error) {
+	if err != nil {
+		p.errs = append(p.errs, p.wrapError(err))
+	}
+}
+
+func (p *Parser[V]) syncTo(ctx context.Context, types []TokenType) *Token {
+	for {
+		token := p.peek(ctx)
+		if token.Type == TokenTypeEOF || slices.Contains(types, token.Type) {
+			return token
+		}
+
+		_ = p.nextToken(ctx)
+	}
+}
+
 func (p *Parser[V]) pushState(states ...ParseState[V]) {
 	for i := len(states) - 1; i >= 0; i-- {
 		p.stateStack.push(states[i])
@@ -300,19 +467,39 @@ func (p *Parser[V]) setRoot(root *Node[V]) {
 }
 
 func (p *Parser[V]) peek(ctx context.Context) *Token {
-	if p.next != nil {
-		return p.next
-	}
+	return p.peekN(ctx, 1)
+}
+
+// peekN returns the k-th upcoming token, reading tokens from the token source
+// as needed. If the token source is exhausted, the EOF token is returned.
+func (p *Parser[V]) peekN(ctx context.Context, k int) *Token {
+	k = max(k, 1)
+
+	for p.lookahead.len() < k {
+		if n := p.lookahead.len(); n > 0 && p.lookahead.at(n-1).Type == TokenTypeEOF {
+			// Do not read past the end of the token source.
+			return p.lookahead.at(n - 1)
+		}
 
-	p.next = p.tokens.NextToken(ctx)
+		p.lookahead.push(p.tokens.NextToken(ctx))
+	}
 
-	return p.next
+	return p.lookahead.at(k - 1)
 }
 
 func (p *Parser[V]) nextToken(ctx context.Context) *Token {
 	l := p.peek(ctx)
-	p.next = nil
+	p.lookahead.pop()
 	p.token = l
+	p.index++
+
+	if len(p.marks) > 0 {
+		p.consumed = append(p.consumed, l)
+	}
+
+	if l.Type != TokenTypeEOF {
+		p.end = l.End
+	}
 
 	return p.token
 }
@@ -324,26 +511,41 @@ func (p *Parser[V]) push(v V) *Node[V] {
 
 func (p *Parser[V]) addNodeHere(v V) *Node[V] {
 	n := p.newNode(v)
-	p.node.Children = append(p.node.Children, n)
-	n.Parent = p.node
+	p.appendNode(n)
 
 	return n
 }
 
+// appendNode adds n as the last child of the current node.
+func (p *Parser[V]) appendNode(n *Node[V]) {
+	parent := p.node
+	children := parent.Children
+	p.record(func() { parent.Children = children })
+
+	parent.Children = append(parent.Children, n)
+	n.Parent = parent
+
+	p.extendEnd(parent, n.End)
+}
+
 func (p *Parser[V]) newNode(v V) *Node[V] {
-	var start Position
+	var start, end Position
 	if p.token != nil {
 		start = p.token.Start
+		end = p.token.End
 	}
 
 	return &Node[V]{
 		Value: v,
 		Start: start,
+		End:   end,
 	}
 }
 
 func (p *Parser[V]) climb() *Node[V] {
 	n := p.node
+	p.closeNode(n)
+
 	if p.node.Parent != nil {
 		p.node = p.node.Parent
 	}
@@ -351,6 +553,32 @@ func (p *Parser[V]) climb() *Node[V] {
 	return n
 }
 
+// closeNode extends the end position of n and its ancestors to the end of the
+// last consumed token.
+func (p *Parser[V]) closeNode(n *Node[V]) {
+	p.extendEnd(n, p.end)
+}
+
+// extendEnd extends the end position of n and its ancestors to cover pos.
+func (p *Parser[V]) extendEnd(n *Node[V], pos Position) {
+	if pos.Line == 0 {
+		// pos is not a valid position.
+		return
+	}
+
+	for ; n != nil; n = n.Parent {
+		if n.End.Line != 0 && n.End.Offset >= pos.Offset {
+			// Ancestors already cover n so we can stop here.
+			return
+		}
+
+		node, end := n, n.End
+		p.record(func() { node.End = end })
+
+		n.End = pos
+	}
+}
+
 //nolint:ireturn // returning the generic interface is needed to return the previous value.
 func (p *Parser[V]) replace(v V) V {
 	node := p.newNode(v)
@@ -360,7 +588,11 @@ func (p *Parser[V]) replace(v V) V {
 	if node.Parent != nil {
 		for i := range node.Parent.Children {
 			if node.Parent.Children[i] == p.node {
+				parent, old := node.Parent, p.node
+				p.record(func() { parent.Children[i] = old })
+
 				node.Parent.Children[i] = node
+
 				break
 			}
 		}
@@ -368,6 +600,13 @@ func (p *Parser[V]) replace(v V) V {
 
 	// Replace children. Preserve nil, non-nil slice.
 	if p.node.Children != nil {
+		old := p.node
+		p.record(func() {
+			for _, child := range old.Children {
+				child.Parent = old
+			}
+		})
+
 		node.Children = make([]*Node[V], len(p.node.Children))
 		for i := range p.node.Children {
 			node.Children[i] = p.node.Children[i]
@@ -380,6 +619,13 @@ func (p *Parser[V]) replace(v V) V {
 		p.root = node
 	}
 
+	// The new node covers at least the span of the old node.
+	if node.End.Line == 0 || node.End.Offset < p.node.End.Offset {
+		node.End = p.node.End
+	}
+
+	p.closeNode(node)
+
 	oldVal := p.node.Value
 	p.node = node
 
@@ -16,6 +16,8 @@ package lexparse
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -82,6 +84,11 @@ func TestParser_new(t *testing.T) {
 			Line:   1,
 			Column: 1,
 		},
+		End: Position{
+			Offset: 0,
+			Line:   1,
+			Column: 1,
+		},
 	}
 	if diff := cmp.Diff(expectedRoot, p.root); diff != "" {
 		t.Fatalf("NewParser: p.root (-want, +got): \n%s", diff)
@@ -110,6 +117,11 @@ func TestParser_parse_op2(t *testing.T) {
 			Line:   1,
 			Column: 1,
 		},
+		End: Position{
+			Offset: 15,
+			Line:   1,
+			Column: 16,
+		},
 		Children: []*Node[string]{
 			{
 				Value: "push",
@@ -118,6 +130,11 @@ func TestParser_parse_op2(t *testing.T) {
 					Line:   1,
 					Column: 1,
 				},
+				End: Position{
+					Offset: 15,
+					Line:   1,
+					Column: 16,
+				},
 				Children: []*Node[string]{
 					{
 						Value: "1",
@@ -126,6 +143,11 @@ func TestParser_parse_op2(t *testing.T) {
 							Line:   1,
 							Column: 6,
 						},
+						End: Position{
+							Offset: 6,
+							Line:   1,
+							Column: 7,
+						},
 					},
 					{
 						Value: "push",
@@ -134,6 +156,11 @@ func TestParser_parse_op2(t *testing.T) {
 							Line:   1,
 							Column: 8,
 						},
+						End: Position{
+							Offset: 15,
+							Line:   1,
+							Column: 16,
+						},
 						Children: []*Node[string]{
 							{
 								Value: "2",
@@ -142,6 +169,11 @@ func TestParser_parse_op2(t *testing.T) {
 									Line:   1,
 									Column: 13,
 								},
+								End: Position{
+									Offset: 13,
+									Line:   1,
+									Column: 14,
+								},
 							},
 							{
 								Value: "3",
@@ -150,6 +182,11 @@ func TestParser_parse_op2(t *testing.T) {
 									Line:   1,
 									Column: 15,
 								},
+								End: Position{
+									Offset: 15,
+									Line:   1,
+									Column: 16,
+								},
 							},
 						},
 					},
@@ -278,6 +315,11 @@ func TestParserContext_Node(t *testing.T) {
 			Line:   1,
 			Column: 1,
 		},
+		End: Position{
+			Offset: 0,
+			Line:   1,
+			Column: 1,
+		},
 		Children: []*Node[string]{
 			{
 				Value: "A",
@@ -300,6 +342,11 @@ func TestParserContext_Node(t *testing.T) {
 			Line:   1,
 			Column: 1,
 		},
+		End: Position{
+			Offset: 0,
+			Line:   1,
+			Column: 1,
+		},
 		Children: []*Node[string]{
 			{
 				Value: "A",
@@ -393,6 +440,62 @@ func TestParserContext_ClimbPos(t *testing.T) {
 	}
 }
 
+func TestParserContext_End(t *testing.T) {
+	t.Parallel()
+
+	l := NewCustomLexer(strings.NewReader("push a climb b"), &lexWordState{})
+
+	var newNode *Node[string]
+
+	parser := NewParser(l, ParseStateFn(func(ctx *ParserContext[string]) error {
+		_ = ctx.Next()
+		push := ctx.Push("push")
+
+		_ = ctx.Next()
+		ctx.Node("a")
+
+		// Closing the node sets the end to the end of the "climb" token.
+		_ = ctx.Next()
+		if diff := cmp.Diff(push, ctx.Climb()); diff != "" {
+			t.Errorf("Climb: (-want, +got): \n%s", diff)
+		}
+
+		_ = ctx.Next()
+		newNode = ctx.NewNode("b")
+		ctx.SetEnd(newNode)
+
+		return nil
+	}))
+
+	root, err := parser.Parse(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantPushEnd := Position{
+		Offset: 12,
+		Line:   1,
+		Column: 13,
+	}
+	if diff := cmp.Diff(wantPushEnd, root.Children[0].End); diff != "" {
+		t.Errorf("push End: (-want, +got): \n%s", diff)
+	}
+
+	wantEnd := Position{
+		Offset: 14,
+		Line:   1,
+		Column: 15,
+	}
+	if diff := cmp.Diff(wantEnd, newNode.End); diff != "" {
+		t.Errorf("NewNode End: (-want, +got): \n%s", diff)
+	}
+
+	// The root is closed at the end of parsing.
+	if diff := cmp.Diff(wantEnd, root.End); diff != "" {
+		t.Errorf("root End: (-want, +got): \n%s", diff)
+	}
+}
+
 func TestParserContext_Push(t *testing.T) {
 	t.Parallel()
 
@@ -409,6 +512,11 @@ func TestParserContext_Push(t *testing.T) {
 			Line:   1,
 			Column: 1,
 		},
+		End: Position{
+			Offset: 0,
+			Line:   1,
+			Column: 1,
+		},
 		Children: []*Node[string]{
 			{
 				Value: valA,
@@ -435,6 +543,11 @@ func TestParserContext_Push(t *testing.T) {
 			Line:   1,
 			Column: 1,
 		},
+		End: Position{
+			Offset: 0,
+			Line:   1,
+			Column: 1,
+		},
 		Children: []*Node[string]{
 			{
 				Value: "A",
@@ -526,8 +639,14 @@ func TestParserContext_Replace_root(t *testing.T) {
 		t.Errorf("Replace(%q): (-want, +got): \n%s", valA, diff)
 	}
 
+	// The new root covers the span of the old root.
 	expectedRoot := &Node[string]{
 		Value: "A",
+		End: Position{
+			Offset: 0,
+			Line:   1,
+			Column: 1,
+		},
 	}
 
 	// Current node is set to root node.
@@ -603,3 +722,262 @@ func TestNode_String(t *testing.T) {
 		t.Errorf("Node.String() (-want, +got): \n%s", diff)
 	}
 }
+
+func TestParserContext_Report(t *testing.T) {
+	t.Parallel()
+
+	l := NewCustomLexer(strings.NewReader("A bad B bad C"), &lexWordState{})
+
+	parser := NewParser(l, ParseStateFn(func(ctx *ParserContext[string]) error {
+		for {
+			token := ctx.Next()
+			switch token.Value {
+			case "":
+				return nil
+			case "bad":
+				ctx.Report(errParse)
+			default:
+				ctx.Node(token.Value)
+			}
+		}
+	}))
+
+	root, err := parser.Parse(context.Background())
+
+	// The partial tree is returned.
+	var values []string
+	for _, child := range root.Children {
+		values = append(values, child.Value)
+	}
+
+	if diff := cmp.Diff([]string{"A", "B", "C"}, values); diff != "" {
+		t.Errorf("Children (-want +got):\n%s", diff)
+	}
+
+	var errs ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ErrorList, got %T", err)
+	}
+
+	if diff := cmp.Diff("1:3: errParse\n1:9: errParse", errs.Error()); diff != "" {
+		t.Errorf("Error (-want +got):\n%s", diff)
+	}
+
+	if !errors.Is(err, errParse) {
+		t.Errorf("expected error %v, got %v", errParse, err)
+	}
+}
+
+func TestParserContext_Report_stateError(t *testing.T) {
+	t.Parallel()
+
+	l := NewCustomLexer(strings.NewReader("A B"), &lexWordState{})
+
+	parser := NewParser(l, ParseStateFn(func(ctx *ParserContext[string]) error {
+		ctx.Push(ctx.Next().Value)
+		ctx.Report(errParse)
+
+		_ = ctx.Next()
+
+		return errState
+	}))
+
+	root, err := parser.Parse(context.Background())
+
+	if diff := cmp.Diff("1:1: errParse\n1:3: errState", err.Error()); diff != "" {
+		t.Errorf("Error (-want +got):\n%s", diff)
+	}
+
+	if !errors.Is(err, errState) {
+		t.Errorf("expected error %v, got %v", errState, err)
+	}
+
+	// The nodes that were open when the error was returned are closed at the
+	// last consumed token.
+	if diff := cmp.Diff(span(0, 3), root.Span()); diff != "" {
+		t.Errorf("root Span (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(span(0, 3), root.Children[0].Span()); diff != "" {
+		t.Errorf("A Span (-want +got):\n%s", diff)
+	}
+}
+
+func TestParserContext_Report_canceled(t *testing.T) {
+	t.Parallel()
+
+	l := NewCustomLexer(strings.NewReader("A B"), &lexWordState{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var state ParseState[string]
+
+	state = ParseStateFn(func(ctx *ParserContext[string]) error {
+		ctx.Push(ctx.Next().Value)
+		ctx.Report(errParse)
+		_ = ctx.Next()
+
+		// Cancel parsing before the next state is run.
+		cancel()
+		ctx.PushState(state)
+
+		return nil
+	})
+
+	root, err := NewParser(l, state).Parse(ctx)
+
+	// Errors reported before parsing was canceled are returned.
+	if !errors.Is(err, errParse) {
+		t.Errorf("expected error %v, got %v", errParse, err)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+
+	if diff := cmp.Diff(span(0, 3), root.Children[0].Span()); diff != "" {
+		t.Errorf("A Span (-want +got):\n%s", diff)
+	}
+}
+
+func TestParserContext_SyncTo(t *testing.T) {
+	t.Parallel()
+
+	l := NewScanningLexer(strings.NewReader("a b ; c = d ; e"))
+
+	ctx := &ParserContext[string]{
+		Context: context.Background(),
+		p:       NewParser[string](l, nil),
+	}
+
+	token := ctx.SyncTo(';')
+	if diff := cmp.Diff(";", token.Value); diff != "" {
+		t.Errorf("SyncTo (-want +got):\n%s", diff)
+	}
+
+	// The synchronization token is not consumed.
+	if diff := cmp.Diff(token, ctx.Next()); diff != "" {
+		t.Errorf("Next (-want +got):\n%s", diff)
+	}
+
+	token = ctx.SyncTo('=', ';')
+	if diff := cmp.Diff("=", token.Value); diff != "" {
+		t.Errorf("SyncTo (-want +got):\n%s", diff)
+	}
+
+	token = ctx.SyncTo('+')
+	if diff := cmp.Diff(TokenTypeEOF, token.Type); diff != "" {
+		t.Errorf("SyncTo (-want +got):\n%s", diff)
+	}
+}
+
+func TestParserContext_PeekN(t *testing.T) {
+	t.Parallel()
+
+	input := "a . b . c = d"
+
+	lexers := map[string]func() TokenSource{
+		"ScanningLexer": func() TokenSource {
+			return NewScanningLexer(strings.NewReader(input))
+		},
+		"CustomLexer": func() TokenSource {
+			return NewCustomLexer(strings.NewReader(input), &lexWordState{})
+		},
+	}
+
+	for name, newLexer := range lexers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := &ParserContext[string]{
+				Context: context.Background(),
+				p:       NewParser[string](newLexer(), nil),
+			}
+
+			// Look ahead to the assignment before consuming any tokens.
+			if diff := cmp.Diff("=", ctx.PeekN(6).Value); diff != "" {
+				t.Errorf("PeekN(6) (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(ctx.Peek(), ctx.PeekN(1)); diff != "" {
+				t.Errorf("PeekN(1) (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(ctx.Peek(), ctx.PeekN(0)); diff != "" {
+				t.Errorf("PeekN(0) (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff("b", ctx.PeekN(3).Value); diff != "" {
+				t.Errorf("PeekN(3) (-want +got):\n%s", diff)
+			}
+
+			// Consuming tokens shifts the lookahead.
+			var values []string
+			for range 3 {
+				values = append(values, ctx.Next().Value)
+			}
+
+			if diff := cmp.Diff([]string{"a", ".", "b"}, values); diff != "" {
+				t.Errorf("Next (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff("d", ctx.PeekN(4).Value); diff != "" {
+				t.Errorf("PeekN(4) (-want +got):\n%s", diff)
+			}
+
+			// Peeking past the end of the input returns the EOF token.
+			eof := ctx.PeekN(5)
+			if diff := cmp.Diff(TokenTypeEOF, eof.Type); diff != "" {
+				t.Errorf("PeekN(5) (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(eof, ctx.PeekN(100)); diff != "" {
+				t.Errorf("PeekN(100) (-want +got):\n%s", diff)
+			}
+
+			values = nil
+			for token := ctx.Next(); token.Type != TokenTypeEOF; token = ctx.Next() {
+				values = append(values, token.Value)
+			}
+
+			if diff := cmp.Diff([]string{".", "c", "=", "d"}, values); diff != "" {
+				t.Errorf("Next (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTokenRing(t *testing.T) {
+	t.Parallel()
+
+	var ring tokenRing
+
+	// Interleave push and pop so that the ring wraps around as it grows.
+	var got []string
+
+	for i := range 20 {
+		ring.push(&Token{Value: strconv.Itoa(i)})
+
+		if i%3 == 2 {
+			got = append(got, ring.pop().Value)
+		}
+	}
+
+	for ring.len() > 0 {
+		got = append(got, ring.pop().Value)
+	}
+
+	want := make([]string, 0, 20)
+	for i := range 20 {
+		want = append(want, strconv.Itoa(i))
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("tokens (-want +got):\n%s", diff)
+	}
+
+	if ring.pop() != nil {
+		t.Errorf("expected nil token from empty ring")
+	}
+}
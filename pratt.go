@@ -0,0 +1,303 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"io"
+)
+
+// PrefixFn parses an expression that starts with token. token has already been
+// consumed. It returns the root node of the expression.
+type PrefixFn[V comparable] func(ctx *ParserContext[V], token *Token) (*Node[V], error)
+
+// InfixFn parses an expression where token follows the expression left. token
+// has already been consumed. It returns the root node of the expression.
+type InfixFn[V comparable] func(ctx *ParserContext[V], left *Node[V], token *Token) (*Node[V], error)
+
+// Associativity is the associativity of a binary operator.
+type Associativity int
+
+const (
+	// LeftAssoc operators group from the left, i.e. a - b - c is parsed as
+	// (a - b) - c.
+	LeftAssoc Associativity = iota
+
+	// RightAssoc operators group from the right, i.e. a ^ b ^ c is parsed as
+	// a ^ (b ^ c).
+	RightAssoc
+)
+
+// infixRule is a handler for a token that follows an expression.
+type infixRule[V comparable] struct {
+	// bp is the left binding power of the token.
+	bp int
+	fn InfixFn[V]
+}
+
+// Pratt is a Pratt (top down operator precedence) expression parser. Handlers
+// are registered for each [TokenType] that can start an expression (prefix
+// handlers) or follow an expression (infix handlers). Infix handlers have a
+// binding power and tokens with higher binding powers bind more tightly.
+// Binding powers should be greater than zero.
+//
+// Pratt implements [ParseState] and can be used as a state of a [Parser].
+// Expressions can also be parsed from other states by calling [Pratt.Expr].
+//
+// Nodes created by the helper methods such as [Pratt.Binary] are created at
+// the position of their operator token and end at the end of the last token
+// of the expression.
+type Pratt[V comparable] struct {
+	prefix map[TokenType]PrefixFn[V]
+	infix  map[TokenType]infixRule[V]
+}
+
+// NewPratt creates a new Pratt parser with no handlers.
+func NewPratt[V comparable]() *Pratt[V] {
+	return &Pratt[V]{
+		prefix: map[TokenType]PrefixFn[V]{},
+		infix:  map[TokenType]infixRule[V]{},
+	}
+}
+
+// Prefix registers fn as the handler for expressions starting with a token of
+// type typ.
+func (p *Pratt[V]) Prefix(typ TokenType, fn PrefixFn[V]) {
+	p.prefix[typ] = fn
+}
+
+// Infix registers fn as the handler for tokens of type typ following an
+// expression. bp is the binding power of the token.
+func (p *Pratt[V]) Infix(typ TokenType, bp int, fn InfixFn[V]) {
+	p.infix[typ] = infixRule[V]{
+		bp: bp,
+		fn: fn,
+	}
+}
+
+// Literal registers a prefix handler for tokens of type typ that creates a
+// leaf node with the value returned by value. Errors returned by value are
+// returned as an [*Error] at the token.
+func (p *Pratt[V]) Literal(typ TokenType, value func(*Token) (V, error)) {
+	p.Prefix(typ, func(ctx *ParserContext[V], token *Token) (*Node[V], error) {
+		v, err := value(token)
+		if err != nil {
+			return nil, NewTokenError(token, err)
+		}
+
+		return ctx.NewNode(v), nil
+	})
+}
+
+// Unary registers a prefix operator of type typ, such as negation. The
+// operand is parsed with binding power bp and is the child of a node with the
+// value returned by value.
+func (p *Pratt[V]) Unary(typ TokenType, bp int, value func(*Token) V) {
+	p.Prefix(typ, func(ctx *ParserContext[V], token *Token) (*Node[V], error) {
+		n := ctx.NewNode(value(token))
+
+		operand, err := p.Expr(ctx, bp)
+		if err != nil {
+			return nil, err
+		}
+
+		return finishNode(ctx, n, operand), nil
+	})
+}
+
+// Binary registers a binary infix operator of type typ with binding power bp
+// and the given associativity. The left and right operands are the children
+// of a node with the value returned by value.
+func (p *Pratt[V]) Binary(typ TokenType, bp int, assoc Associativity, value func(*Token) V) {
+	rbp := bp
+	if assoc == RightAssoc {
+		rbp = bp - 1
+	}
+
+	p.Infix(typ, bp, func(ctx *ParserContext[V], left *Node[V], token *Token) (*Node[V], error) {
+		n := ctx.NewNode(value(token))
+
+		right, err := p.Expr(ctx, rbp)
+		if err != nil {
+			return nil, err
+		}
+
+		return finishNode(ctx, n, left, right), nil
+	})
+}
+
+// Postfix registers a postfix operator of type typ with binding power bp, such
+// as a factorial. The operand is the child of a node with the value returned
+// by value.
+func (p *Pratt[V]) Postfix(typ TokenType, bp int, value func(*Token) V) {
+	p.Infix(typ, bp, func(ctx *ParserContext[V], left *Node[V], token *Token) (*Node[V], error) {
+		return finishNode(ctx, ctx.NewNode(value(token)), left), nil
+	})
+}
+
+// Group registers a prefix handler for grouping expressions in open and closing
+// tokens, such as parentheses. The grouped expression is returned without
+// creating a new node.
+func (p *Pratt[V]) Group(open, closing TokenType) {
+	p.Prefix(open, func(ctx *ParserContext[V], _ *Token) (*Node[V], error) {
+		n, err := p.Expr(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := ctx.Expect(closing); err != nil {
+			return nil, err
+		}
+
+		return n, nil
+	})
+}
+
+// Ternary registers a right associative conditional operator of type typ with
+// binding power bp, such as a ? b : c, where sep separates the second and third
+// operands. The three operands are the children of a node with the value
+// returned by value.
+func (p *Pratt[V]) Ternary(typ, sep TokenType, bp int, value func(*Token) V) {
+	p.Infix(typ, bp, func(ctx *ParserContext[V], cond *Node[V], token *Token) (*Node[V], error) {
+		n := ctx.NewNode(value(token))
+
+		then, err := p.Expr(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := ctx.Expect(sep); err != nil {
+			return nil, err
+		}
+
+		els, err := p.Expr(ctx, bp-1)
+		if err != nil {
+			return nil, err
+		}
+
+		return finishNode(ctx, n, cond, then, els), nil
+	})
+}
+
+// Call registers a postfix call operator with binding power bp, such as
+// f(a, b), where open and closing surround a list of arguments separated by
+// sep. The callee and arguments are the children of a node with the value
+// returned by value. Call can also be used for index expressions such as a[i].
+func (p *Pratt[V]) Call(open, sep, closing TokenType, bp int, value func(*Token) V) {
+	p.Infix(open, bp, func(ctx *ParserContext[V], callee *Node[V], token *Token) (*Node[V], error) {
+		n := ctx.NewNode(value(token))
+		children := []*Node[V]{callee}
+
+		if ctx.Peek().Type == closing {
+			_ = ctx.Next()
+			return finishNode(ctx, n, children...), nil
+		}
+
+		for {
+			arg, err := p.Expr(ctx, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			children = append(children, arg)
+
+			if ctx.Peek().Type != sep {
+				break
+			}
+
+			_ = ctx.Next()
+		}
+
+		if _, err := ctx.Expect(closing); err != nil {
+			return nil, err
+		}
+
+		return finishNode(ctx, n, children...), nil
+	})
+}
+
+// Expr parses an expression and returns its root node without adding it to the
+// parse tree. Only infix operators with binding powers greater than rbp are
+// parsed as part of the expression. Expr(ctx, 0) parses a complete
+// expression. Expr is typically called by handlers to parse operands.
+func (p *Pratt[V]) Expr(ctx *ParserContext[V], rbp int) (*Node[V], error) {
+	// Check if the context is canceled.
+	select {
+	case <-ctx.Done():
+		//nolint:wrapcheck // We want to return the original context error.
+		return nil, ctx.Err()
+	default:
+	}
+
+	token := ctx.Next()
+
+	prefix, ok := p.prefix[token.Type]
+	if !ok {
+		if token.Type == TokenTypeEOF {
+			return nil, NewTokenError(token, io.ErrUnexpectedEOF)
+		}
+
+		return nil, NewTokenError(token, ErrUnexpectedToken)
+	}
+
+	left, err := prefix(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		infix, ok := p.infix[ctx.Peek().Type]
+		if !ok || infix.bp <= rbp {
+			return left, nil
+		}
+
+		token := ctx.Next()
+
+		left, err = infix.fn(ctx, left, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Run implements [ParseState.Run]. It parses an expression and adds it as a
+// child of the current node.
+func (p *Pratt[V]) Run(ctx *ParserContext[V]) error {
+	n, err := p.Expr(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	ctx.p.appendNode(n)
+
+	return nil
+}
+
+// finishNode adds children to n and closes it at the last consumed token. The
+// start of n is moved back to cover its children, such as the left operand of
+// an infix operator.
+func finishNode[V comparable](ctx *ParserContext[V], n *Node[V], children ...*Node[V]) *Node[V] {
+	for _, child := range children {
+		child.Parent = n
+		n.Children = append(n.Children, child)
+
+		if child.Start.Line != 0 && child.Start.Offset < n.Start.Offset {
+			n.Start = child.Start
+		}
+	}
+
+	ctx.SetEnd(n)
+
+	return n
+}
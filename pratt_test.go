@@ -0,0 +1,266 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestPratt returns a Pratt parser for a small expression language.
+func newTestPratt() *Pratt[string] {
+	p := NewPratt[string]()
+
+	value := func(token *Token) string {
+		return token.Value
+	}
+
+	p.Literal(TokenTypeIdent, func(token *Token) (string, error) {
+		return token.Value, nil
+	})
+	p.Literal(TokenTypeInt, func(token *Token) (string, error) {
+		return token.Value, nil
+	})
+	p.Group('(', ')')
+	p.Ternary('?', ':', 1, value)
+	p.Binary('+', 2, LeftAssoc, value)
+	p.Binary('-', 2, LeftAssoc, value)
+	p.Binary('*', 3, LeftAssoc, value)
+	p.Binary('^', 4, RightAssoc, value)
+	p.Unary('-', 5, value)
+	p.Postfix('!', 6, value)
+	p.Call('(', ',', ')', 7, func(*Token) string { return "call" })
+	p.Call('[', ',', ']', 7, func(*Token) string { return "index" })
+
+	return p
+}
+
+// sexpr formats the tree rooted at n as an s-expression.
+func sexpr(n *Node[string]) string {
+	if len(n.Children) == 0 {
+		return n.Value
+	}
+
+	parts := []string{n.Value}
+	for _, child := range n.Children {
+		if child.Parent != n {
+			parts = append(parts, "<bad parent>")
+		}
+
+		parts = append(parts, sexpr(child))
+	}
+
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func TestPratt(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input string
+		want  string
+	}{
+		"literal": {
+			input: "a",
+			want:  "a",
+		},
+		"precedence": {
+			input: "a + b * c",
+			want:  "(+ a (* b c))",
+		},
+		"left assoc": {
+			input: "a - b - c",
+			want:  "(- (- a b) c)",
+		},
+		"right assoc": {
+			input: "a ^ b ^ c",
+			want:  "(^ a (^ b c))",
+		},
+		"group": {
+			input: "(a + b) * c",
+			want:  "(* (+ a b) c)",
+		},
+		"unary": {
+			input: "-a * b",
+			want:  "(* (- a) b)",
+		},
+		"postfix": {
+			input: "-a!",
+			want:  "(- (! a))",
+		},
+		"ternary": {
+			input: "a ? b : c ? d : e",
+			want:  "(? a b (? c d e))",
+		},
+		"call": {
+			input: "f(a, b + c)(d)",
+			want:  "(call (call f a (+ b c)) d)",
+		},
+		"call no args": {
+			input: "f()",
+			want:  "(call f)",
+		},
+		"index": {
+			input: "a[1] + b[2]",
+			want:  "(+ (index a 1) (index b 2))",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewParser(NewScanningLexer(strings.NewReader(tc.input)), ParseState[string](newTestPratt()))
+
+			root, err := p.Parse(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(1, len(root.Children)); diff != "" {
+				t.Fatalf("Children (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want, sexpr(root.Children[0])); diff != "" {
+				t.Errorf("Parse (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPratt_span(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input string
+
+		// want is the span of the expression's root node.
+		want Span
+	}{
+		"infix": {
+			input: "a + f(b)",
+			want:  span(0, 8),
+		},
+		"postfix": {
+			input: "a!",
+			want:  span(0, 2),
+		},
+		"call": {
+			input: "f(a, b)",
+			want:  span(0, 7),
+		},
+		"index": {
+			input: "a[b] + c",
+			want:  span(0, 8),
+		},
+		"ternary": {
+			input: "a ? b : c",
+			want:  span(0, 9),
+		},
+		"nested": {
+			input: "a * b + c!",
+			want:  span(0, 10),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewParser(NewScanningLexer(strings.NewReader(tc.input)), ParseState[string](newTestPratt()))
+
+			root, err := p.Parse(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, root.Children[0].Span()); diff != "" {
+				t.Errorf("Span (-want +got):\n%s", diff)
+			}
+
+			// Every node covers the spans of its children.
+			for n := range root.All() {
+				for _, child := range n.Children {
+					if n.Start.Offset > child.Start.Offset || n.End.Offset < child.End.Offset {
+						t.Errorf("node %q %v does not cover child %q %v", n.Value, n.Span(), child.Value, child.Span())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPratt_errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input   string
+		wantErr error
+		want    Span
+	}{
+		"unexpected eof": {
+			input:   "a +",
+			wantErr: io.ErrUnexpectedEOF,
+			want:    span(3, 3),
+		},
+		"no prefix": {
+			input:   "a + *",
+			wantErr: ErrUnexpectedToken,
+			want:    span(4, 5),
+		},
+		"unclosed group": {
+			input:   "(a b",
+			wantErr: ErrUnexpectedToken,
+			want:    span(3, 4),
+		},
+		"missing ternary separator": {
+			input:   "a ? b c",
+			wantErr: ErrUnexpectedToken,
+			want:    span(6, 7),
+		},
+		"unclosed call": {
+			input:   "f(a, b",
+			wantErr: io.ErrUnexpectedEOF,
+			want:    span(6, 6),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewParser(NewScanningLexer(strings.NewReader(tc.input)), ParseState[string](newTestPratt()))
+
+			_, err := p.Parse(context.Background())
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Parse: want %v, got %v", tc.wantErr, err)
+			}
+
+			var lpErr *Error
+			if !errors.As(err, &lpErr) {
+				t.Fatalf("Parse: want *Error, got %T", err)
+			}
+
+			if diff := cmp.Diff(tc.want, lpErr.Span); diff != "" {
+				t.Errorf("Span (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// treeGlyphs are the strings used to draw the branches of a tree.
+type treeGlyphs struct {
+	// branch precedes a node that has following siblings.
+	branch string
+
+	// last precedes a node that is the last of its siblings.
+	last string
+
+	// vertical is indentation below a node that has following siblings.
+	vertical string
+
+	// space is indentation below a node that is the last of its siblings.
+	space string
+}
+
+// TreePrinter writes parse trees as indented text. For example,
+//
+//	a (1:1)
+//	├── b (1:3)
+//	│   ├── d (1:5)
+//	│   └── e (1:7)
+//	└── c (1:9)
+//	    └── f (1:11)
+//
+// The zero value prints each node's value with [fmt.Sprint] followed by its
+// start position.
+type TreePrinter[V comparable] struct {
+	// Label returns the label printed for a node's value. Values are formatted
+	// with [fmt.Sprint] if Label is nil.
+	Label func(V) string
+
+	// HidePositions omits the start position of each node.
+	HidePositions bool
+
+	// MaxDepth is the maximum number of levels of the tree that are printed.
+	// Omitted children are printed as "...". If MaxDepth is zero, the whole
+	// tree is printed.
+	MaxDepth int
+
+	// ASCII draws the tree with ASCII characters rather than box-drawing
+	// characters.
+	ASCII bool
+}
+
+// Fprint writes the tree rooted at root to w. Output is buffered and written
+// as the tree is traversed so the text of the whole tree is never held in
+// memory.
+func (p *TreePrinter[V]) Fprint(w io.Writer, root *Node[V]) error {
+	glyphs := treeGlyphs{
+		branch:   "├── ",
+		last:     "└── ",
+		vertical: "│   ",
+		space:    "    ",
+	}
+	if p.ASCII {
+		glyphs = treeGlyphs{
+			branch:   "|-- ",
+			last:     "`-- ",
+			vertical: "|   ",
+			space:    "    ",
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+
+	p.fprint(bw, &glyphs, root, "", "", 1)
+
+	// bufio.Writer errors are sticky so any write error is returned by Flush.
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing tree: %w", err)
+	}
+
+	return nil
+}
+
+// fprint writes n and its children. The line for n begins with prefix followed
+// by connector and its children are indented with prefix.
+func (p *TreePrinter[V]) fprint(
+	w *bufio.Writer,
+	glyphs *treeGlyphs,
+	n *Node[V],
+	prefix, connector string,
+	depth int,
+) {
+	_, _ = w.WriteString(prefix)
+	_, _ = w.WriteString(connector)
+	_, _ = w.WriteString(p.label(n))
+	_ = w.WriteByte('\n')
+
+	if len(n.Children) == 0 {
+		return
+	}
+
+	switch connector {
+	case glyphs.branch:
+		prefix += glyphs.vertical
+	case glyphs.last:
+		prefix += glyphs.space
+	}
+
+	if p.MaxDepth > 0 && depth >= p.MaxDepth {
+		_, _ = w.WriteString(prefix)
+		_, _ = w.WriteString(glyphs.last)
+		_, _ = w.WriteString("...\n")
+
+		return
+	}
+
+	for i, child := range n.Children {
+		connector := glyphs.branch
+		if i == len(n.Children)-1 {
+			connector = glyphs.last
+		}
+
+		p.fprint(w, glyphs, child, prefix, connector, depth+1)
+	}
+}
+
+// label returns the text printed for n.
+func (p *TreePrinter[V]) label(n *Node[V]) string {
+	var label string
+	if p.Label != nil {
+		label = p.Label(n.Value)
+	} else {
+		label = fmt.Sprint(n.Value)
+	}
+
+	if p.HidePositions {
+		return label
+	}
+
+	return label + " (" + n.Start.String() + ")"
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var errPrinterTest = errors.New("test error")
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errPrinterTest
+}
+
+func TestTreePrinter(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+	root.Start = Position{Offset: 0, Line: 1, Column: 1}
+	root.Children[0].Start = Position{Offset: 2, Line: 1, Column: 3}
+
+	testCases := []struct {
+		name    string
+		printer *TreePrinter[string]
+		want    string
+	}{
+		{
+			name:    "default",
+			printer: &TreePrinter[string]{},
+			want: `a (1:1)
+├── b (1:3)
+│   ├── d (0:0)
+│   └── e (0:0)
+└── c (0:0)
+    └── f (0:0)
+`,
+		},
+		{
+			name: "label",
+			printer: &TreePrinter[string]{
+				Label:         strings.ToUpper,
+				HidePositions: true,
+			},
+			want: `A
+├── B
+│   ├── D
+│   └── E
+└── C
+    └── F
+`,
+		},
+		{
+			name: "ascii",
+			printer: &TreePrinter[string]{
+				HidePositions: true,
+				ASCII:         true,
+			},
+			want: "a\n" +
+				"|-- b\n" +
+				"|   |-- d\n" +
+				"|   `-- e\n" +
+				"`-- c\n" +
+				"    `-- f\n",
+		},
+		{
+			name: "max depth",
+			printer: &TreePrinter[string]{
+				HidePositions: true,
+				MaxDepth:      2,
+			},
+			want: `a
+├── b
+│   └── ...
+└── c
+    └── ...
+`,
+		},
+		{
+			name: "root only",
+			printer: &TreePrinter[string]{
+				HidePositions: true,
+				MaxDepth:      1,
+			},
+			want: `a
+└── ...
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			if err := tc.printer.Fprint(&b, root); err != nil {
+				t.Fatalf("Fprint: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.want, b.String()); diff != "" {
+				t.Errorf("Fprint (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTreePrinter_error(t *testing.T) {
+	t.Parallel()
+
+	err := (&TreePrinter[string]{}).Fprint(errWriter{}, newTestTree())
+	if !errors.Is(err, errPrinterTest) {
+		t.Errorf("Fprint: want %v, got %v", errPrinterTest, err)
+	}
+}
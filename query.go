@@ -0,0 +1,466 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidQuery is the cause of errors returned by [CompileQuery] for
+// invalid queries.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Predicate reports whether a node matches. Predicates are referred to by name
+// in queries compiled by [CompileQuery]. args are the arguments given in the
+// query, if any.
+type Predicate[V comparable] func(n *Node[V], args ...string) bool
+
+// Query is a compiled query that selects nodes in a tree by their structure.
+// Queries are compiled with [CompileQuery].
+//
+// A query is a path of steps separated by / or //. Each step selects nodes
+// relative to the nodes selected by the previous step. A step following a /
+// selects children and a step following a // selects descendants. Queries are
+// evaluated against a virtual parent of the node passed to [Query.Select] so
+// /name matches that node itself. A query that does not start with / or //
+// is treated as if it starts with //.
+//
+// Each step starts with the name of a [Predicate] that nodes must match, or *
+// to match any node. It can be followed by filters in square brackets:
+//
+//   - [name] keeps nodes matching the named predicate.
+//   - [name="arg"] keeps nodes matching the named predicate called with the
+//     argument. The argument is a quoted Go string.
+//   - [N] keeps the N-th node, starting at 1, selected from each node of the
+//     previous step after applying the previous filters.
+//
+// Finally, a step can end with @name to capture the selected node as name.
+// Captured nodes are returned by [Query.Matches]. For example,
+//
+//	//section[name="database"]@section/property[empty]
+//
+// selects the property nodes matching the "empty" predicate that are children
+// of section nodes matching the "name" predicate called with "database".
+type Query[V comparable] struct {
+	expr  string
+	steps []queryStep[V]
+}
+
+// queryStep is a single step of a query.
+type queryStep[V comparable] struct {
+	// descendant is true if the step selects descendants rather than
+	// children.
+	descendant bool
+
+	// test is the predicate that nodes must match. It is nil for *.
+	test Predicate[V]
+
+	filters []queryFilter[V]
+
+	// capture is the name the selected nodes are captured as, if any.
+	capture string
+}
+
+// queryFilter is a filter of a query step. Either pos or pred is set.
+type queryFilter[V comparable] struct {
+	// pos is the 1-based position of the node to keep.
+	pos int
+
+	pred Predicate[V]
+	args []string
+}
+
+// Match is a node selected by a [Query] along with the nodes captured while
+// selecting it.
+type Match[V comparable] struct {
+	// Node is the selected node.
+	Node *Node[V]
+
+	// Captures are the nodes captured by name. It is nil if the query has no
+	// captures.
+	Captures map[string]*Node[V]
+}
+
+// CompileQuery compiles a query expression. preds are the predicates that can
+// be referred to by name in the expression. See [Query] for the syntax.
+// Errors are returned as an [*Error] at the position of the problem in expr
+// caused by [ErrInvalidQuery].
+func CompileQuery[V comparable](expr string, preds map[string]Predicate[V]) (*Query[V], error) {
+	qp := &queryParser[V]{
+		expr:  expr,
+		preds: preds,
+	}
+
+	steps, err := qp.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Query[V]{
+		expr:  expr,
+		steps: steps,
+	}, nil
+}
+
+// String returns the query expression.
+func (q *Query[V]) String() string {
+	return q.expr
+}
+
+// Select returns the nodes in the tree rooted at root that are selected by the
+// query in depth-first order. Each node is returned once.
+func (q *Query[V]) Select(root *Node[V]) []*Node[V] {
+	var nodes []*Node[V]
+
+	seen := map[*Node[V]]bool{}
+
+	for _, m := range q.Matches(root) {
+		if !seen[m.Node] {
+			seen[m.Node] = true
+			nodes = append(nodes, m.Node)
+		}
+	}
+
+	// Sort the nodes in depth-first order.
+	order := map[*Node[V]]int{}
+	for n := range root.All() {
+		order[n] = len(order)
+	}
+
+	slices.SortFunc(nodes, func(a, b *Node[V]) int {
+		return order[a] - order[b]
+	})
+
+	return nodes
+}
+
+// Matches returns the matches of the query in the tree rooted at root. A node
+// can be returned more than once if it is selected by different paths through
+// the tree.
+func (q *Query[V]) Matches(root *Node[V]) []Match[V] {
+	if root == nil {
+		return nil
+	}
+
+	// A nil Node is the virtual parent of root.
+	matches := []Match[V]{{}}
+
+	for _, step := range q.steps {
+		var next []Match[V]
+
+		for _, m := range matches {
+			for _, n := range step.apply(root, m.Node) {
+				captures := m.Captures
+				if step.capture != "" {
+					captures = maps.Clone(captures)
+					if captures == nil {
+						captures = map[string]*Node[V]{}
+					}
+
+					captures[step.capture] = n
+				}
+
+				next = append(next, Match[V]{
+					Node:     n,
+					Captures: captures,
+				})
+			}
+		}
+
+		matches = next
+	}
+
+	return matches
+}
+
+// apply returns the nodes selected by the step relative to n. A nil n is the
+// virtual parent of root.
+func (s *queryStep[V]) apply(root, n *Node[V]) []*Node[V] {
+	var nodes []*Node[V]
+
+	switch {
+	case n == nil && s.descendant:
+		nodes = slices.Collect(root.All())
+	case n == nil:
+		nodes = []*Node[V]{root}
+	case s.descendant:
+		nodes = slices.Collect(n.Descendants())
+	default:
+		nodes = slices.Clone(n.Children)
+	}
+
+	if s.test != nil {
+		nodes = slices.DeleteFunc(nodes, func(n *Node[V]) bool {
+			return !s.test(n)
+		})
+	}
+
+	for _, f := range s.filters {
+		if f.pos > 0 {
+			if f.pos > len(nodes) {
+				return nil
+			}
+
+			nodes = nodes[f.pos-1 : f.pos]
+
+			continue
+		}
+
+		nodes = slices.DeleteFunc(nodes, func(n *Node[V]) bool {
+			return !f.pred(n, f.args...)
+		})
+	}
+
+	return nodes
+}
+
+// queryParser parses query expressions.
+type queryParser[V comparable] struct {
+	expr  string
+	pos   int
+	preds map[string]Predicate[V]
+}
+
+func (qp *queryParser[V]) parse() ([]queryStep[V], error) {
+	var steps []queryStep[V]
+
+	qp.skipSpace()
+
+	if qp.eof() {
+		return nil, qp.errorf("empty query")
+	}
+
+	for !qp.eof() {
+		var descendant bool
+
+		switch {
+		case qp.consume("//"):
+			descendant = true
+		case qp.consume("/"):
+		case len(steps) == 0:
+			descendant = true
+		default:
+			return nil, qp.errorf("expected '/'")
+		}
+
+		step, err := qp.step(descendant)
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, step)
+
+		qp.skipSpace()
+	}
+
+	return steps, nil
+}
+
+func (qp *queryParser[V]) step(descendant bool) (queryStep[V], error) {
+	step := queryStep[V]{
+		descendant: descendant,
+	}
+
+	qp.skipSpace()
+
+	if !qp.consume("*") {
+		start := qp.pos
+
+		name := qp.ident()
+		if name == "" {
+			return step, qp.errorf("expected name or '*'")
+		}
+
+		pred, err := qp.pred(name, start)
+		if err != nil {
+			return step, err
+		}
+
+		step.test = pred
+	}
+
+	for qp.skipSpace(); qp.consume("["); qp.skipSpace() {
+		f, err := qp.filter()
+		if err != nil {
+			return step, err
+		}
+
+		step.filters = append(step.filters, f)
+	}
+
+	if qp.consume("@") {
+		step.capture = qp.ident()
+		if step.capture == "" {
+			return step, qp.errorf("expected capture name")
+		}
+	}
+
+	return step, nil
+}
+
+func (qp *queryParser[V]) filter() (queryFilter[V], error) {
+	var f queryFilter[V]
+
+	qp.skipSpace()
+
+	start := qp.pos
+
+	if num := qp.number(); num != "" {
+		pos, err := strconv.Atoi(num)
+		if err != nil || pos < 1 {
+			qp.pos = start
+
+			return f, qp.errorf("invalid position %q", num)
+		}
+
+		f.pos = pos
+	} else {
+		name := qp.ident()
+		if name == "" {
+			return f, qp.errorf("expected name or position")
+		}
+
+		pred, err := qp.pred(name, start)
+		if err != nil {
+			return f, err
+		}
+
+		f.pred = pred
+
+		qp.skipSpace()
+
+		if qp.consume("=") {
+			qp.skipSpace()
+
+			arg, err := qp.str()
+			if err != nil {
+				return f, err
+			}
+
+			f.args = []string{arg}
+		}
+	}
+
+	qp.skipSpace()
+
+	if !qp.consume("]") {
+		return f, qp.errorf("expected ']'")
+	}
+
+	return f, nil
+}
+
+// pred returns the predicate with the given name found at offset start.
+func (qp *queryParser[V]) pred(name string, start int) (Predicate[V], error) {
+	pred, ok := qp.preds[name]
+	if !ok || pred == nil {
+		qp.pos = start
+		return nil, qp.errorf("unknown predicate %q", name)
+	}
+
+	return pred, nil
+}
+
+// ident consumes and returns an identifier made up of letters, digits, '_',
+// and '-' that does not start with a digit or '-'.
+func (qp *queryParser[V]) ident() string {
+	start := qp.pos
+
+	for !qp.eof() {
+		c := qp.expr[qp.pos]
+		if !isIdentByte(c) || (qp.pos == start && (isDigit(c) || c == '-')) {
+			break
+		}
+
+		qp.pos++
+	}
+
+	return qp.expr[start:qp.pos]
+}
+
+// number consumes and returns a sequence of digits.
+func (qp *queryParser[V]) number() string {
+	start := qp.pos
+	for !qp.eof() && isDigit(qp.expr[qp.pos]) {
+		qp.pos++
+	}
+
+	return qp.expr[start:qp.pos]
+}
+
+// str consumes and returns a quoted string.
+func (qp *queryParser[V]) str() (string, error) {
+	quoted, err := strconv.QuotedPrefix(qp.expr[qp.pos:])
+	if err != nil {
+		return "", qp.errorf("expected quoted string")
+	}
+
+	s, err := strconv.Unquote(quoted)
+	if err != nil {
+		return "", qp.errorf("invalid quoted string")
+	}
+
+	qp.pos += len(quoted)
+
+	return s, nil
+}
+
+func (qp *queryParser[V]) consume(s string) bool {
+	if strings.HasPrefix(qp.expr[qp.pos:], s) {
+		qp.pos += len(s)
+		return true
+	}
+
+	return false
+}
+
+func (qp *queryParser[V]) skipSpace() {
+	for !qp.eof() && (qp.expr[qp.pos] == ' ' || qp.expr[qp.pos] == '\t') {
+		qp.pos++
+	}
+}
+
+func (qp *queryParser[V]) eof() bool {
+	return qp.pos >= len(qp.expr)
+}
+
+// errorf returns an [*Error] at the current position in the expression.
+func (qp *queryParser[V]) errorf(format string, args ...any) error {
+	end := qp.pos
+	if !qp.eof() {
+		end++
+	}
+
+	err := NewError(Span{
+		Start: Position{Offset: qp.pos, Line: 1, Column: qp.pos + 1},
+		End:   Position{Offset: end, Line: 1, Column: end + 1},
+	}, ErrInvalidQuery)
+	err.Message = fmt.Sprintf(format, args...)
+
+	return err
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '-' || isDigit(c) || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newQueryTestTree returns a tree of INI-like sections and properties. Values
+// are of the form "kind:name=value".
+func newQueryTestTree() *Node[string] {
+	return addParent(&Node[string]{
+		Value: "root",
+		Children: []*Node[string]{
+			{
+				Value: "section:owner",
+				Children: []*Node[string]{
+					{Value: "property:name=John"},
+					{Value: "property:org="},
+				},
+			},
+			{
+				Value: "section:database",
+				Children: []*Node[string]{
+					{Value: "property:server=192.0.2.62"},
+					{Value: "property:port="},
+					{Value: "property:file="},
+				},
+			},
+		},
+	})
+}
+
+// queryTestPreds are predicates for the tree returned by newQueryTestTree.
+func queryTestPreds() map[string]Predicate[string] {
+	kind := func(k string) Predicate[string] {
+		return func(n *Node[string], _ ...string) bool {
+			return strings.HasPrefix(n.Value, k+":")
+		}
+	}
+
+	return map[string]Predicate[string]{
+		"root": func(n *Node[string], _ ...string) bool {
+			return n.Value == "root"
+		},
+		"section":  kind("section"),
+		"property": kind("property"),
+		"name": func(n *Node[string], args ...string) bool {
+			_, rest, _ := strings.Cut(n.Value, ":")
+			name, _, _ := strings.Cut(rest, "=")
+
+			return len(args) == 1 && name == args[0]
+		},
+		"empty": func(n *Node[string], _ ...string) bool {
+			return strings.HasSuffix(n.Value, "=")
+		},
+	}
+}
+
+func TestQuery_Select(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expr string
+		want []string
+	}{
+		"root": {
+			expr: "/*",
+			want: []string{"root"},
+		},
+		"child": {
+			expr: "/*/section",
+			want: []string{"section:owner", "section:database"},
+		},
+		"child not root": {
+			expr: "/section",
+			want: nil,
+		},
+		"descendant": {
+			expr: "//section",
+			want: []string{"section:owner", "section:database"},
+		},
+		"implicit descendant": {
+			expr: "property[empty]",
+			want: []string{"property:org=", "property:port=", "property:file="},
+		},
+		"filter argument": {
+			expr: `//section[name="database"]/property[empty]`,
+			want: []string{"property:port=", "property:file="},
+		},
+		"position": {
+			expr: "/root/section[2]/*[1]",
+			want: []string{"property:server=192.0.2.62"},
+		},
+		"position per parent": {
+			expr: "section/property[2]",
+			want: []string{"property:org=", "property:port="},
+		},
+		"position after filter": {
+			expr: "//property[empty][1]",
+			want: []string{"property:org="},
+		},
+		"position out of range": {
+			expr: "/root/section[3]",
+			want: nil,
+		},
+		"descendants deduplicated": {
+			expr: "//*//property[name=\"port\"]",
+			want: []string{"property:port="},
+		},
+		"spaces": {
+			expr: ` // section [ name = "owner" ] / * `,
+			want: []string{"property:name=John", "property:org="},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := CompileQuery(tc.expr, queryTestPreds())
+			if err != nil {
+				t.Fatalf("CompileQuery: %v", err)
+			}
+
+			var got []string
+			for _, n := range q.Select(newQueryTestTree()) {
+				got = append(got, n.Value)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Select (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuery_Matches(t *testing.T) {
+	t.Parallel()
+
+	q, err := CompileQuery("section@section/property[empty]@prop", queryTestPreds())
+	if err != nil {
+		t.Fatalf("CompileQuery: %v", err)
+	}
+
+	var got [][]string
+	for _, m := range q.Matches(newQueryTestTree()) {
+		got = append(got, []string{m.Node.Value, m.Captures["section"].Value, m.Captures["prop"].Value})
+	}
+
+	want := [][]string{
+		{"property:org=", "section:owner", "property:org="},
+		{"property:port=", "section:database", "property:port="},
+		{"property:file=", "section:database", "property:file="},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Matches (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompileQuery_errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expr string
+		want string
+	}{
+		"empty": {
+			expr: " ",
+			want: "1:2: invalid query: empty query",
+		},
+		"missing name": {
+			expr: "//[1]",
+			want: "1:3: invalid query: expected name or '*'",
+		},
+		"unknown predicate": {
+			expr: "section/prop",
+			want: `1:9: invalid query: unknown predicate "prop"`,
+		},
+		"unknown filter": {
+			expr: "section[foo]",
+			want: `1:9: invalid query: unknown predicate "foo"`,
+		},
+		"unclosed filter": {
+			expr: "section[empty",
+			want: "1:14: invalid query: expected ']'",
+		},
+		"invalid position": {
+			expr: "section[0]",
+			want: `1:9: invalid query: invalid position "0"`,
+		},
+		"unquoted argument": {
+			expr: "section[name=owner]",
+			want: "1:14: invalid query: expected quoted string",
+		},
+		"missing capture": {
+			expr: "section@",
+			want: "1:9: invalid query: expected capture name",
+		},
+		"missing separator": {
+			expr: "section property",
+			want: "1:9: invalid query: expected '/'",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CompileQuery(tc.expr, queryTestPreds())
+			if !errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("CompileQuery: want %v, got %v", ErrInvalidQuery, err)
+			}
+
+			if diff := cmp.Diff(tc.want, err.Error()); diff != "" {
+				t.Errorf("Error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,10 +17,10 @@ package lexparse
 import (
 	"context"
 	"errors"
-	"fmt"
 	"io"
 	"os"
 	"text/scanner"
+	"unicode/utf8"
 )
 
 var errScanner = errors.New("scanner error")
@@ -49,6 +49,14 @@ const (
 
 	// TokenTypeComment represents a comment.
 	TokenTypeComment = TokenType(scanner.Comment)
+
+	// TokenTypeError represents input that could not be lexed. The Err field
+	// of the token holds the error. Lexers only emit error tokens if
+	// configured to do so. See [CustomLexerContext.EmitError] and
+	// [ScanningLexer.SetErrorTokens].
+	//
+	// NOTE: text/scanner uses the values -1 to -9 internally.
+	TokenTypeError TokenType = -10
 )
 
 // ScanningLexer is a lexer that uses the text/scanner package to tokenize
@@ -56,13 +64,184 @@ const (
 type ScanningLexer struct {
 	s *scanner.Scanner
 
+	// src records the input read by the scanner that has not yet been used to
+	// compute positions.
+	src *recordingReader
+
+	// pos is the position of the first byte in src.
+	pos Position
+
+	// cols counts the columns of pos.
+	cols columnCounter
+
 	// err is the first error the lexer encountered.
 	err error
+
+	// errorTokens indicates that scanner errors are returned as error tokens
+	// rather than stopping the lexer.
+	errorTokens bool
+
+	// tokenErr is the error reported by the scanner while scanning the
+	// current token when errorTokens is set.
+	tokenErr error
+
+	// errStart is the start of the token that the scanner reported an error
+	// in, converted when the error was reported. Converting positions
+	// advances pos so the token's start must be converted before the end of
+	// the error.
+	errStart *scanPosition
+
+	// operators maps operators to their token types.
+	operators map[string]TokenType
+
+	// operatorPrefixes is the set of prefixes of the operators.
+	operatorPrefixes map[string]bool
+
+	// keywords classifies identifiers as keywords.
+	keywords *KeywordTable
+}
+
+// scanPosition is a position reported by the scanner and its converted
+// [Position].
+type scanPosition struct {
+	offset int
+	pos    Position
+}
+
+// recordingReader is an [io.Reader] that records the data read from the
+// underlying reader.
+type recordingReader struct {
+	r   io.Reader
+	buf []byte
+
+	// ahead holds data read from the underlying reader by peek that has not
+	// yet been read.
+	ahead []byte
+
+	// err is the error returned by the underlying reader while peeking.
+	err error
+}
+
+// Read implements [io.Reader.Read].
+func (r *recordingReader) Read(p []byte) (int, error) {
+	if len(r.ahead) > 0 {
+		n := copy(p, r.ahead)
+		r.ahead = r.ahead[n:]
+		r.buf = append(r.buf, p[:n]...)
+
+		return n, nil
+	}
+
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.r.Read(p)
+	r.buf = append(r.buf, p[:n]...)
+
+	//nolint:wrapcheck // errors must be returned unwrapped to the scanner.
+	return n, err
+}
+
+// peek returns at least n bytes of the recorded data followed by the data
+// that has not yet been read, if available, without consuming it.
+func (r *recordingReader) peek(n int) []byte {
+	for len(r.buf)+len(r.ahead) < n && r.err == nil {
+		p := make([]byte, n-len(r.buf)-len(r.ahead))
+
+		m, err := r.r.Read(p)
+		r.ahead = append(r.ahead, p[:m]...)
+		r.err = err
+	}
+
+	if len(r.ahead) == 0 {
+		return r.buf
+	}
+
+	return append(r.buf[:len(r.buf):len(r.buf)], r.ahead...)
+}
+
+// ScanningLexerOption is an option that configures a [ScanningLexer].
+type ScanningLexerOption func(*scanningLexerOptions)
+
+type scanningLexerOptions struct {
+	// mode is the scanner's Mode.
+	mode uint
+
+	// whitespace is the scanner's Whitespace.
+	whitespace uint64
+
+	// isIdentRune is the scanner's IsIdentRune.
+	isIdentRune func(ch rune, i int) bool
+
+	// skipComments indicates that comments are skipped.
+	skipComments bool
+
+	// operators maps multi-character operators to their token types.
+	operators map[string]TokenType
+
+	// keywords classifies identifiers as keywords.
+	keywords *KeywordTable
+}
+
+// WithMode sets the [scanner.Scanner.Mode] bits that control which tokens are
+// recognized. The default mode recognizes identifiers, floats, chars, strings,
+// raw strings, and comments. Unlike [scanner.GoTokens], comments are returned
+// as tokens.
+func WithMode(mode uint) ScanningLexerOption {
+	return func(o *scanningLexerOptions) {
+		o.mode = mode
+	}
+}
+
+// WithWhitespace sets the [scanner.Scanner.Whitespace] bits that control which
+// characters are skipped. The default is [scanner.GoWhitespace]. Characters
+// that are not skipped are returned as single rune tokens. For example,
+// newlines can be returned as tokens of type '\n' with:
+//
+//	lexparse.WithWhitespace(scanner.GoWhitespace &^ (1 << '\n'))
+func WithWhitespace(whitespace uint64) ScanningLexerOption {
+	return func(o *scanningLexerOptions) {
+		o.whitespace = whitespace
+	}
+}
+
+// WithIdentRune sets the [scanner.Scanner.IsIdentRune] function that reports
+// whether ch is accepted as the i'th character of an identifier. By default,
+// identifiers are Go identifiers.
+func WithIdentRune(isIdentRune func(ch rune, i int) bool) ScanningLexerOption {
+	return func(o *scanningLexerOptions) {
+		o.isIdentRune = isIdentRune
+	}
+}
+
+// WithSkipComments configures the lexer to recognize comments and skip them
+// rather than returning them as tokens. It applies regardless of the mode set
+// by [WithMode].
+func WithSkipComments() ScanningLexerOption {
+	return func(o *scanningLexerOptions) {
+		o.skipComments = true
+	}
 }
 
 // NewScanningLexer creates a new ScanningLexer that reads from the given
-// [io.Reader].
-func NewScanningLexer(r io.Reader) *ScanningLexer {
+// [io.Reader]. The underlying [scanner.Scanner] can be configured by passing
+// [ScanningLexerOption] values.
+func NewScanningLexer(r io.Reader, opts ...ScanningLexerOption) *ScanningLexer {
+	o := &scanningLexerOptions{
+		// Configure the scanner to be more generic and to not skip Go comments.
+		mode: scanner.ScanIdents | scanner.ScanFloats | scanner.ScanChars |
+			scanner.ScanStrings | scanner.ScanRawStrings | scanner.ScanComments,
+		whitespace: scanner.GoWhitespace,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.skipComments {
+		o.mode |= scanner.ScanComments | scanner.SkipComments
+	}
+
 	var fileName string
 
 	file, ok := r.(*os.File)
@@ -70,51 +249,196 @@ func NewScanningLexer(r io.Reader) *ScanningLexer {
 		fileName = file.Name()
 	}
 
-	l := ScanningLexer{}
-	l.s = &scanner.Scanner{
-		Error: func(s *scanner.Scanner, msg string) {
-			if l.err == nil {
-				l.err = fmt.Errorf("%w: %s: %s", errScanner, s.Position, msg)
-			}
+	l := ScanningLexer{
+		src: &recordingReader{r: r},
+		pos: Position{
+			Offset: 0,
+			Line:   1,
+			Column: 1,
 		},
+		operators:        o.operators,
+		operatorPrefixes: operatorPrefixes(o.operators),
+		keywords:         o.keywords,
+	}
+	l.s = &scanner.Scanner{
 		Position: scanner.Position{
 			Filename: fileName,
 		},
 	}
-	l.s = l.s.Init(r)
-	// Configure the scanner to be more generic and to not skip Go comments.
-	l.s.Mode = scanner.ScanIdents | scanner.ScanFloats | scanner.ScanChars |
-		scanner.ScanStrings | scanner.ScanRawStrings | scanner.ScanComments
+	l.s = l.s.Init(l.src)
+	// NOTE: Init resets the Error function so it must be set afterwards.
+	l.s.Error = func(s *scanner.Scanner, msg string) {
+		switch {
+		case l.errorTokens:
+			if l.tokenErr == nil {
+				l.tokenErr = l.scanError(s, msg)
+			}
+		case l.err == nil:
+			l.err = l.scanError(s, msg)
+		}
+	}
+	l.s.Mode = o.mode
+	l.s.Whitespace = o.whitespace
+	l.s.IsIdentRune = o.isIdentRune
 
 	return &l
 }
 
 // NextToken implements Lexer.NextToken. It returns the next token from
 // the input stream.
-func (l *ScanningLexer) NextToken(_ context.Context) *Token {
+func (l *ScanningLexer) NextToken(ctx context.Context) *Token {
 	if l.err != nil {
-		return l.newToken(TokenTypeEOF)
+		return l.eofToken()
 	}
 
-	return l.newToken(TokenType(l.s.Scan()))
+	// Return EOF if the context is done/canceled.
+	select {
+	case <-ctx.Done():
+		l.err = ctx.Err()
+		return l.eofToken()
+	default:
+	}
+
+	token := l.newToken(TokenType(l.s.Scan()))
+
+	if l.tokenErr != nil {
+		// NOTE: If the error was found at the end of the input, the scanner
+		// will return EOF again on the next call to Scan.
+		token.Type = TokenTypeError
+		token.Err = l.tokenErr
+		l.tokenErr = nil
+
+		return token
+	}
+
+	// Single rune tokens have the value of the rune as their type.
+	if token.Type > 0 {
+		return l.matchOperator(token)
+	}
+
+	if token.Type == TokenTypeIdent {
+		if typ, ok := l.keywords.Lookup(token.Value); ok {
+			token.Type = typ
+		}
+	}
+
+	return token
 }
 
 // Err implements Lexer.Err. It returns the first error encountered by
-// the lexer, if any.
+// the lexer, if any. Errors are returned as an [*Error] unless the error was
+// caused by the context being done.
 func (l *ScanningLexer) Err() error {
 	return l.err
 }
 
+// scanError returns an [*Error] for an error reported by the scanner.
+func (l *ScanningLexer) scanError(s *scanner.Scanner, msg string) *Error {
+	// NOTE: If the scanner's Position is not valid the error occurred outside
+	// of a token.
+	start := s.Pos()
+	if s.IsValid() {
+		start = s.Position
+	}
+
+	span := Span{
+		Start: l.position(start),
+	}
+
+	if s.IsValid() && l.errStart == nil {
+		l.errStart = &scanPosition{
+			offset: start.Offset,
+			pos:    span.Start,
+		}
+	}
+
+	span.End = l.position(s.Pos())
+
+	err := NewError(span, errScanner)
+	err.Message = msg
+
+	return err
+}
+
 func (l *ScanningLexer) newToken(typ TokenType) *Token {
+	var start Position
+
+	// NOTE: The token's start was already converted if the scanner reported
+	// an error while scanning it.
+	if l.errStart != nil && l.errStart.offset == l.s.Offset {
+		start = l.errStart.pos
+	} else {
+		start = l.position(l.s.Position)
+	}
+
+	l.errStart = nil
+
 	return &Token{
 		Type:  typ,
 		Value: l.s.TokenText(),
-		Start: Position(l.s.Position),
-		End:   Position(l.s.Pos()),
+		Start: start,
+		End:   l.position(l.s.Pos()),
+	}
+}
+
+// eofToken returns an EOF token at the current position of the scanner.
+func (l *ScanningLexer) eofToken() *Token {
+	pos := l.position(l.s.Pos())
+
+	return &Token{
+		Type:  TokenTypeEOF,
+		Start: pos,
+		End:   pos,
+	}
+}
+
+// position converts a position reported by the scanner to a [Position],
+// counting columns according to the lexer's [ColumnMode].
+func (l *ScanningLexer) position(scanPos scanner.Position) Position {
+	if scanPos.Offset < l.pos.Offset {
+		// The scanner's positions should never move backwards. Fall back to
+		// the scanner's own accounting.
+		return Position(scanPos)
+	}
+
+	var consumed int
+	for l.pos.Offset < scanPos.Offset && consumed < len(l.src.buf) {
+		rn, size := utf8.DecodeRune(l.src.buf[consumed:])
+		consumed += size
+		l.cols.advance(&l.pos, rn, size)
 	}
+
+	l.src.buf = l.src.buf[consumed:]
+
+	pos := l.pos
+	pos.Filename = scanPos.Filename
+
+	return pos
 }
 
 // SetFilename sets the filename in the lexer's positional information.
 func (l *ScanningLexer) SetFilename(name string) {
 	l.s.Filename = name
 }
+
+// SetErrorTokens sets whether errors reported by the scanner are returned as
+// tokens of type [TokenTypeError] rather than stopping the lexer. When enabled,
+// the token that caused the error is returned as an error token and lexing
+// continues. It should be called before lexing begins.
+func (l *ScanningLexer) SetErrorTokens(enabled bool) {
+	l.errorTokens = enabled
+}
+
+// SetColumnMode sets how the lexer counts the Column of positions. It should
+// be called before lexing begins.
+func (l *ScanningLexer) SetColumnMode(mode ColumnMode) {
+	l.cols.mode = mode
+}
+
+// SetTabWidth sets the distance between tab stops used when counting columns.
+// A tab advances the column to the next tab stop. If width is zero, the
+// default, a tab is counted like any other character. It should be called
+// before lexing begins.
+func (l *ScanningLexer) SetTabWidth(width int) {
+	l.cols.tabWidth = width
+}
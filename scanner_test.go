@@ -19,6 +19,7 @@ import (
 	"strings"
 	"testing"
 	"text/scanner"
+	"unicode"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -135,6 +136,68 @@ func TestScannerLexer_NextToken(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "multi-byte input",
+			input: "日本 = 1",
+			expected: []*Token{
+				{
+					Type:  TokenType(scanner.Ident),
+					Value: "日本",
+					Start: Position{
+						Offset: 0,
+						Line:   1,
+						Column: 1,
+					},
+					End: Position{
+						Offset: 6,
+						Line:   1,
+						Column: 3,
+					},
+				},
+				{
+					Type:  TokenType('='),
+					Value: "=",
+					Start: Position{
+						Offset: 7,
+						Line:   1,
+						Column: 4,
+					},
+					End: Position{
+						Offset: 8,
+						Line:   1,
+						Column: 5,
+					},
+				},
+				{
+					Type:  TokenType(scanner.Int),
+					Value: "1",
+					Start: Position{
+						Offset: 9,
+						Line:   1,
+						Column: 6,
+					},
+					End: Position{
+						Offset: 10,
+						Line:   1,
+						Column: 7,
+					},
+				},
+				{
+					Type:  TokenTypeEOF,
+					Value: "",
+					Start: Position{
+						Offset: 10,
+						Line:   1,
+						Column: 7,
+					},
+					End: Position{
+						Offset: 10,
+						Line:   1,
+						Column: 7,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -194,3 +257,119 @@ func TestScanningLexer_SetFilename(t *testing.T) {
 		}
 	})
 }
+
+func TestScanningLexer_options(t *testing.T) {
+	t.Parallel()
+
+	type token struct {
+		Type  TokenType
+		Value string
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     []ScanningLexerOption
+		expected []token
+	}{
+		{
+			name:  "default",
+			input: "a-b // c\n1.5",
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{'-', "-"},
+				{TokenTypeIdent, "b"},
+				{TokenTypeComment, "// c"},
+				{TokenTypeFloat, "1.5"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "mode",
+			input: "a 1.5",
+			opts:  []ScanningLexerOption{WithMode(scanner.ScanInts)},
+			expected: []token{
+				{'a', "a"},
+				{TokenTypeInt, "1"},
+				{'.', "."},
+				{TokenTypeInt, "5"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "whitespace",
+			input: "a\nb",
+			opts:  []ScanningLexerOption{WithWhitespace(scanner.GoWhitespace &^ (1 << '\n'))},
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{'\n', "\n"},
+				{TokenTypeIdent, "b"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "ident rune",
+			input: "$a-b c",
+			opts: []ScanningLexerOption{
+				WithIdentRune(func(ch rune, i int) bool {
+					return ch == '$' || unicode.IsLetter(ch) || (ch == '-' && i > 0)
+				}),
+			},
+			expected: []token{
+				{TokenTypeIdent, "$a-b"},
+				{TokenTypeIdent, "c"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "skip comments",
+			input: "a // b\nc /* d */",
+			opts:  []ScanningLexerOption{WithSkipComments()},
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{TokenTypeIdent, "c"},
+				{TokenTypeEOF, ""},
+			},
+		},
+		{
+			name:  "skip comments with mode",
+			input: "a // b\n1",
+			opts: []ScanningLexerOption{
+				WithSkipComments(),
+				WithMode(scanner.ScanIdents | scanner.ScanInts),
+			},
+			expected: []token{
+				{TokenTypeIdent, "a"},
+				{TokenTypeInt, "1"},
+				{TokenTypeEOF, ""},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := NewScanningLexer(strings.NewReader(tc.input), tc.opts...)
+
+			var got []token
+
+			for {
+				tok := l.NextToken(context.Background())
+				got = append(got, token{tok.Type, tok.Value})
+
+				if tok.Type == TokenTypeEOF {
+					break
+				}
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected tokens (-want +got):\n%s", diff)
+			}
+
+			if err := l.Err(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
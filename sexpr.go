@@ -0,0 +1,98 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteSExpr writes the tree rooted at root to w as an S-expression. Leaf nodes
+// are written as their label and other nodes as a list of their label followed
+// by their children. Nodes whose children are all leaves are written on a
+// single line. Otherwise, each child is written on its own indented line. For
+// example,
+//
+//	(a
+//	  (b d e)
+//	  (c f))
+//
+// The label of each node is returned by label or is formatted with
+// [fmt.Sprint] if label is nil. Labels that are empty or contain spaces,
+// parentheses, or quotes are quoted.
+func WriteSExpr[V comparable](w io.Writer, root *Node[V], label func(V) string) error {
+	if label == nil {
+		label = func(v V) string {
+			return fmt.Sprint(v)
+		}
+	}
+
+	var b strings.Builder
+
+	writeSExpr(&b, root, label, 0)
+	b.WriteString("\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing S-expression: %w", err)
+	}
+
+	return nil
+}
+
+func writeSExpr[V comparable](b *strings.Builder, n *Node[V], label func(V) string, depth int) {
+	atom := sexprAtom(label(n.Value))
+
+	if len(n.Children) == 0 {
+		b.WriteString(atom)
+		return
+	}
+
+	b.WriteString("(")
+	b.WriteString(atom)
+
+	leaves := true
+
+	for _, child := range n.Children {
+		if len(child.Children) > 0 {
+			leaves = false
+			break
+		}
+	}
+
+	for _, child := range n.Children {
+		if leaves {
+			b.WriteString(" ")
+		} else {
+			b.WriteString("\n")
+			b.WriteString(strings.Repeat("  ", depth+1))
+		}
+
+		writeSExpr(b, child, label, depth+1)
+	}
+
+	b.WriteString(")")
+}
+
+// sexprAtom returns s quoted if needed to be read as a single S-expression
+// atom.
+func sexprAtom(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\r\n()\";") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteSExpr(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+	root.Children[1].Children[0].Value = "f g"
+	root.AppendChild(&Node[string]{Value: ""})
+
+	var b strings.Builder
+	if err := WriteSExpr(&b, root, nil); err != nil {
+		t.Fatalf("WriteSExpr: %v", err)
+	}
+
+	want := `(a
+  (b d e)
+  (c "f g")
+  "")
+`
+
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("WriteSExpr (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Span is a range of the input between two positions. The Start position is
+// inclusive and the End position is exclusive. Positions are compared by
+// their byte Offset.
+type Span struct {
+	// Start is the position of the first byte in the span.
+	Start Position
+
+	// End is the position immediately after the last byte in the span.
+	End Position
+}
+
+// String returns a string representation of the Span.
+func (s Span) String() string {
+	return s.Start.String() + "-" + strconv.Itoa(s.End.Line) + ":" + strconv.Itoa(s.End.Column)
+}
+
+// Len returns the length of the span in bytes.
+func (s Span) Len() int {
+	return max(s.End.Offset-s.Start.Offset, 0)
+}
+
+// IsZero returns true if s is the zero value.
+func (s Span) IsZero() bool {
+	return s == Span{}
+}
+
+// Contains returns true if the position p is within the span. Positions in a
+// different file are never contained in the span.
+func (s Span) Contains(p Position) bool {
+	return p.Filename == s.Start.Filename &&
+		p.Offset >= s.Start.Offset &&
+		p.Offset < s.End.Offset
+}
+
+// Overlaps returns true if s and other share at least one byte of the input.
+// Empty spans do not overlap any span.
+func (s Span) Overlaps(other Span) bool {
+	return s.Start.Filename == other.Start.Filename &&
+		s.Len() > 0 && other.Len() > 0 &&
+		s.Start.Offset < other.End.Offset &&
+		other.Start.Offset < s.End.Offset
+}
+
+// Union returns the smallest span that covers both s and other. If either span
+// is the zero value the other span is returned. This allows a span covering a
+// number of spans to be built starting from the zero value.
+func (s Span) Union(other Span) Span {
+	if s.IsZero() {
+		return other
+	}
+
+	if other.IsZero() {
+		return s
+	}
+
+	union := s
+	if other.Start.Offset < union.Start.Offset {
+		union.Start = other.Start
+	}
+
+	if other.End.Offset > union.End.Offset {
+		union.End = other.End
+	}
+
+	return union
+}
+
+// SourceFile holds the contents of an input so that the text of a [Span] can
+// be retrieved after lexing and parsing.
+type SourceFile struct {
+	// name is the name of the file.
+	name string
+
+	// src is the contents of the file.
+	src []byte
+
+	// lines holds the byte offset of the start of each line.
+	lines []int
+}
+
+// NewSourceFile creates a new SourceFile with the given name and contents. The
+// name should match the filename used by the lexer, if any. The contents
+// should not be modified after calling NewSourceFile.
+func NewSourceFile(name string, src []byte) *SourceFile {
+	lines := []int{0}
+
+	for i, b := range src {
+		if b == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+
+	return &SourceFile{
+		name:  name,
+		src:   src,
+		lines: lines,
+	}
+}
+
+// Name returns the name of the file.
+func (f *SourceFile) Name() string {
+	return f.name
+}
+
+// LineCount returns the number of lines in the file. A file ending with a
+// newline has an empty final line.
+func (f *SourceFile) LineCount() int {
+	return len(f.lines)
+}
+
+// Text returns the text of the input covered by the span. Offsets outside of
+// the file are clamped to the file contents.
+func (f *SourceFile) Text(s Span) string {
+	start := f.clamp(s.Start.Offset)
+	end := max(f.clamp(s.End.Offset), start)
+
+	return string(f.src[start:end])
+}
+
+// Line returns the text of the line with the given line number, starting at
+// 1, without the trailing line ending. An empty string is returned if the line
+// does not exist.
+func (f *SourceFile) Line(n int) string {
+	if n < 1 || n > len(f.lines) {
+		return ""
+	}
+
+	start := f.lines[n-1]
+
+	end := len(f.src)
+	if n < len(f.lines) {
+		end = f.lines[n]
+	}
+
+	line := string(f.src[start:end])
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+
+	return line
+}
+
+// Lines returns the full text of each line containing the span, without line
+// endings. An empty span returns the line containing its start position.
+func (f *SourceFile) Lines(s Span) []string {
+	first := f.lineAt(s.Start.Offset)
+
+	last := first
+	if s.End.Offset > s.Start.Offset {
+		// The end offset is exclusive so find the line of the last byte.
+		last = f.lineAt(s.End.Offset - 1)
+	}
+
+	lines := make([]string, 0, last-first+1)
+	for n := first; n <= last; n++ {
+		lines = append(lines, f.Line(n))
+	}
+
+	return lines
+}
+
+// lineAt returns the line number, starting at 1, containing the given byte
+// offset.
+func (f *SourceFile) lineAt(offset int) int {
+	offset = f.clamp(offset)
+
+	// Find the first line that starts after offset. The line containing
+	// offset is the line before it.
+	return sort.Search(len(f.lines), func(i int) bool {
+		return f.lines[i] > offset
+	})
+}
+
+// clamp returns offset limited to the bounds of the file contents.
+func (f *SourceFile) clamp(offset int) int {
+	return min(max(offset, 0), len(f.src))
+}
@@ -0,0 +1,306 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// span returns a span on the first line between the given offsets.
+func span(start, end int) Span {
+	return Span{
+		Start: Position{
+			Offset: start,
+			Line:   1,
+			Column: start + 1,
+		},
+		End: Position{
+			Offset: end,
+			Line:   1,
+			Column: end + 1,
+		},
+	}
+}
+
+func TestSpan_Contains(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		span     Span
+		pos      Position
+		expected bool
+	}{
+		{
+			name:     "start",
+			span:     span(2, 5),
+			pos:      span(2, 2).Start,
+			expected: true,
+		},
+		{
+			name:     "middle",
+			span:     span(2, 5),
+			pos:      span(4, 4).Start,
+			expected: true,
+		},
+		{
+			name:     "end",
+			span:     span(2, 5),
+			pos:      span(5, 5).Start,
+			expected: false,
+		},
+		{
+			name:     "before",
+			span:     span(2, 5),
+			pos:      span(1, 1).Start,
+			expected: false,
+		},
+		{
+			name:     "empty",
+			span:     span(2, 2),
+			pos:      span(2, 2).Start,
+			expected: false,
+		},
+		{
+			name: "different file",
+			span: span(2, 5),
+			pos: Position{
+				Filename: "other.txt",
+				Offset:   3,
+				Line:     1,
+				Column:   4,
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, tc.span.Contains(tc.pos)); diff != "" {
+				t.Errorf("Contains (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSpan_Overlaps(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		a        Span
+		b        Span
+		expected bool
+	}{
+		{
+			name:     "overlapping",
+			a:        span(2, 5),
+			b:        span(4, 8),
+			expected: true,
+		},
+		{
+			name:     "contained",
+			a:        span(2, 8),
+			b:        span(4, 5),
+			expected: true,
+		},
+		{
+			name:     "adjacent",
+			a:        span(2, 5),
+			b:        span(5, 8),
+			expected: false,
+		},
+		{
+			name:     "disjoint",
+			a:        span(2, 3),
+			b:        span(5, 8),
+			expected: false,
+		},
+		{
+			name:     "empty",
+			a:        span(4, 4),
+			b:        span(2, 8),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, tc.a.Overlaps(tc.b)); diff != "" {
+				t.Errorf("a.Overlaps(b) (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expected, tc.b.Overlaps(tc.a)); diff != "" {
+				t.Errorf("b.Overlaps(a) (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSpan_Union(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		a        Span
+		b        Span
+		expected Span
+	}{
+		{
+			name:     "overlapping",
+			a:        span(2, 5),
+			b:        span(4, 8),
+			expected: span(2, 8),
+		},
+		{
+			name:     "contained",
+			a:        span(2, 8),
+			b:        span(4, 5),
+			expected: span(2, 8),
+		},
+		{
+			name:     "disjoint",
+			a:        span(5, 8),
+			b:        span(1, 2),
+			expected: span(1, 8),
+		},
+		{
+			name:     "zero",
+			a:        Span{},
+			b:        span(4, 5),
+			expected: span(4, 5),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, tc.a.Union(tc.b)); diff != "" {
+				t.Errorf("a.Union(b) (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expected, tc.b.Union(tc.a)); diff != "" {
+				t.Errorf("b.Union(a) (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSourceFile(t *testing.T) {
+	t.Parallel()
+
+	src := NewSourceFile("test.txt", []byte("Hello\r\nWörld\nfoo bar\n"))
+
+	if diff := cmp.Diff(4, src.LineCount()); diff != "" {
+		t.Errorf("LineCount (-want +got):\n%s", diff)
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			span     Span
+			expected string
+		}{
+			{span: span(0, 5), expected: "Hello"},
+			{span: span(7, 13), expected: "Wörld"},
+			{span: span(3, 10), expected: "lo\r\nWö"},
+			{span: span(5, 5), expected: ""},
+			{span: span(18, 100), expected: "bar\n"},
+			{span: span(9, 3), expected: ""},
+		}
+
+		for _, tc := range testCases {
+			if diff := cmp.Diff(tc.expected, src.Text(tc.span)); diff != "" {
+				t.Errorf("Text(%v) (-want +got):\n%s", tc.span, diff)
+			}
+		}
+	})
+
+	t.Run("Line", func(t *testing.T) {
+		t.Parallel()
+
+		expected := []string{"", "Hello", "Wörld", "foo bar", "", ""}
+		for n, want := range expected {
+			if diff := cmp.Diff(want, src.Line(n)); diff != "" {
+				t.Errorf("Line(%d) (-want +got):\n%s", n, diff)
+			}
+		}
+	})
+
+	t.Run("Lines", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			span     Span
+			expected []string
+		}{
+			{span: span(1, 3), expected: []string{"Hello"}},
+			{span: span(3, 9), expected: []string{"Hello", "Wörld"}},
+			{span: span(0, 7), expected: []string{"Hello"}},
+			{span: span(15, 15), expected: []string{"foo bar"}},
+			{span: span(0, 22), expected: []string{"Hello", "Wörld", "foo bar"}},
+			{span: span(22, 22), expected: []string{""}},
+		}
+
+		for _, tc := range testCases {
+			if diff := cmp.Diff(tc.expected, src.Lines(tc.span)); diff != "" {
+				t.Errorf("Lines(%v) (-want +got):\n%s", tc.span, diff)
+			}
+		}
+	})
+}
+
+func TestSourceFile_nodeText(t *testing.T) {
+	t.Parallel()
+
+	input := "push héllo push wörld x climb y"
+
+	root, err := testParse(t, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	src := NewSourceFile("", []byte(input))
+
+	if diff := cmp.Diff(input, src.Text(root.Span())); diff != "" {
+		t.Errorf("root (-want +got):\n%s", diff)
+	}
+
+	push := root.Children[0].Children[1]
+	if diff := cmp.Diff("push wörld x climb", src.Text(push.Span())); diff != "" {
+		t.Errorf("push (-want +got):\n%s", diff)
+	}
+
+	token := &Token{
+		Start: push.Start,
+		End:   push.Children[0].End,
+	}
+	if diff := cmp.Diff("push wörld", src.Text(token.Span())); diff != "" {
+		t.Errorf("token (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{strings.TrimSpace(input)}, src.Lines(push.Span())); diff != "" {
+		t.Errorf("Lines (-want +got):\n%s", diff)
+	}
+}
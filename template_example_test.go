@@ -99,10 +99,6 @@ func (n *tmplNode) String() string {
 	}
 }
 
-func lexTokenErr(err error, t *lexparse.Token) error {
-	return fmt.Errorf("%w: %s", err, t)
-}
-
 // lexText tokenizes normal text.
 //
 //nolint:ireturn // returning interface is required to satisfy lexparse.LexState.
@@ -149,8 +145,7 @@ func lexCode(ctx *lexparse.CustomLexerContext) (lexparse.LexState, error) {
 	case symbolRegexp.MatchString(string(rn)):
 		return lexparse.LexStateFn(lexSymbol), nil
 	default:
-		return nil, fmt.Errorf("%w: %q; line: %d, column: %d", errRune,
-			rn, ctx.Pos().Line, ctx.Pos().Column)
+		return nil, fmt.Errorf("%w: %q", errRune, rn)
 	}
 }
 
@@ -190,8 +185,7 @@ func lexSymbol(ctx *lexparse.CustomLexerContext) (lexparse.LexState, error) {
 			return lexparse.LexStateFn(lexText), nil
 		default:
 			if rn := ctx.Peek(); !symbolRegexp.MatchString(string(rn)) {
-				return nil, fmt.Errorf("symbol: %w: %q; line: %d, column: %d",
-					errRune, rn, ctx.Pos().Line, ctx.Pos().Column)
+				return nil, fmt.Errorf("symbol: %w: %q", errRune, rn)
 			}
 		}
 
@@ -264,7 +258,7 @@ func parseVar(ctx *lexparse.ParserContext[*tmplNode]) error {
 	case lexTypeIdentifier:
 		// Validate the variable name.
 		if !idenRegexp.MatchString(token.Value) {
-			return lexTokenErr(fmt.Errorf("%w: invalid variable name", errIdentifier), token)
+			return lexparse.NewTokenError(token, fmt.Errorf("%w: invalid variable name", errIdentifier))
 		}
 
 		// Add a variable node.
@@ -277,58 +271,49 @@ func parseVar(ctx *lexparse.ParserContext[*tmplNode]) error {
 	case lexparse.TokenTypeEOF:
 		return fmt.Errorf("%w: parsing variable name", io.ErrUnexpectedEOF)
 	default:
-		return lexTokenErr(errIdentifier, token)
+		return lexparse.NewTokenError(token, errIdentifier)
 	}
 }
 
 // parseVarEnd handles var end (e.g. '}}').
 func parseVarEnd(ctx *lexparse.ParserContext[*tmplNode]) error {
-	switch token := ctx.Next(); token.Type {
-	case lexTypeVarEnd:
-		// Go back to parsing template init state.
-		ctx.PushState(lexparse.ParseStateFn(parseSeq))
-		return nil
-	case lexparse.TokenTypeEOF:
-		return fmt.Errorf("%w: unclosed variable, expected %q", io.ErrUnexpectedEOF, tokenVarEnd)
-	default:
-		return fmt.Errorf("%w: expected %q", lexTokenErr(errIdentifier, token), tokenVarEnd)
+	if _, err := ctx.Expect(lexTypeVarEnd, tokenVarEnd); err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
 	}
+
+	// Go back to parsing template init state.
+	ctx.PushState(lexparse.ParseStateFn(parseSeq))
+
+	return nil
 }
 
 // parseBranch handles the start if conditional block.
 func parseBranch(ctx *lexparse.ParserContext[*tmplNode]) error {
-	switch token := ctx.Next(); token.Type {
-	case lexTypeIdentifier:
-		if token.Value != tokenIf {
-			return fmt.Errorf("%w: expected %q", errIdentifier, tokenIf)
-		}
+	if _, err := ctx.Expect(lexTypeIdentifier, tokenIf); err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
+	}
 
-		// Add a branch node.
-		_ = ctx.Push(&tmplNode{
-			typ: nodeTypeBranch,
-		})
+	// Add a branch node.
+	_ = ctx.Push(&tmplNode{
+		typ: nodeTypeBranch,
+	})
 
-		ctx.PushState(
-			// Parse the conditional expression.  Currently only a simple
-			// variable is supported.
-			lexparse.ParseStateFn(parseVar),
+	ctx.PushState(
+		// Parse the conditional expression.  Currently only a simple
+		// variable is supported.
+		lexparse.ParseStateFn(parseVar),
 
-			// Parse the '%}'
-			lexparse.ParseStateFn(parseBlockEnd),
+		// Parse the '%}'
+		lexparse.ParseStateFn(parseBlockEnd),
 
-			// Parse the if block.
-			lexparse.ParseStateFn(parseIf),
+		// Parse the if block.
+		lexparse.ParseStateFn(parseIf),
 
-			// Parse an 'else' (or 'endif')
-			lexparse.ParseStateFn(parseElse),
-		)
+		// Parse an 'else' (or 'endif')
+		lexparse.ParseStateFn(parseElse),
+	)
 
-		return nil
-	case lexparse.TokenTypeEOF:
-		return fmt.Errorf("%w: expected %q", io.ErrUnexpectedEOF, tokenIf)
-	default:
-		return lexTokenErr(errIdentifier, token)
-	}
+	return nil
 }
 
 // parseIf handles the if body.
@@ -351,12 +336,12 @@ func parseElse(ctx *lexparse.ParserContext[*tmplNode]) error {
 	case lexTypeIdentifier:
 		// Validate we are at a sequence node.
 		if cur := ctx.Pos(); cur.Value.typ != nodeTypeSeq {
-			return lexTokenErr(errIdentifier, token)
+			return lexparse.NewTokenError(token, errIdentifier)
 		}
 	case lexparse.TokenTypeEOF:
 		return fmt.Errorf("%w: unclosed if block, looking for %q or %q", io.ErrUnexpectedEOF, tokenElse, tokenEndif)
 	default:
-		return lexTokenErr(errIdentifier, token)
+		return lexparse.NewTokenError(token, errIdentifier)
 	}
 
 	switch token.Value {
@@ -369,7 +354,7 @@ func parseElse(ctx *lexparse.ParserContext[*tmplNode]) error {
 
 		// Validate that we are in a conditional and there isn't already an else branch.
 		if cur := ctx.Pos(); cur.Value.typ != nodeTypeBranch || len(cur.Children) != 2 {
-			return lexTokenErr(errIdentifier, token)
+			return lexparse.NewTokenError(token, errIdentifier)
 		}
 
 		// Add an else sequence node to the conditional.
@@ -390,7 +375,7 @@ func parseElse(ctx *lexparse.ParserContext[*tmplNode]) error {
 	case tokenEndif:
 		ctx.PushState(lexparse.ParseStateFn(parseEndif))
 	default:
-		return lexTokenErr(fmt.Errorf("%w: looking for %q or %q", errIdentifier, tokenElse, tokenEndif), token)
+		return lexparse.NewTokenError(token, fmt.Errorf("%w: looking for %q or %q", errIdentifier, tokenElse, tokenEndif))
 	}
 
 	return nil
@@ -398,32 +383,25 @@ func parseElse(ctx *lexparse.ParserContext[*tmplNode]) error {
 
 // parseEndif handles either an endif block.
 func parseEndif(ctx *lexparse.ParserContext[*tmplNode]) error {
-	switch token := ctx.Next(); token.Type {
-	case lexTypeIdentifier:
-		if token.Value != tokenEndif {
-			return lexTokenErr(fmt.Errorf("%w: looking for %q", errIdentifier, tokenEndif), token)
-		}
+	if _, err := ctx.Expect(lexTypeIdentifier, tokenEndif); err != nil {
+		return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
+	}
 
-		// Climb out of the sequence node.
-		ctx.Climb()
+	// Climb out of the sequence node.
+	ctx.Climb()
 
-		// Climb out of the branch node.
-		ctx.Climb()
+	// Climb out of the branch node.
+	ctx.Climb()
 
-		ctx.PushState(
-			// parse the '%}'
-			lexparse.ParseStateFn(parseBlockEnd),
+	ctx.PushState(
+		// parse the '%}'
+		lexparse.ParseStateFn(parseBlockEnd),
 
-			// Go back to parsing a sequence.
-			lexparse.ParseStateFn(parseSeq),
-		)
+		// Go back to parsing a sequence.
+		lexparse.ParseStateFn(parseSeq),
+	)
 
-		return nil
-	case lexparse.TokenTypeEOF:
-		return fmt.Errorf("%w: looking for %q", io.ErrUnexpectedEOF, tokenEndif)
-	default:
-		return lexTokenErr(errIdentifier, token)
-	}
+	return nil
 }
 
 // parseBlockStart handles the start of a template block '{%'.
@@ -435,7 +413,7 @@ func parseBlockStart(ctx *lexparse.ParserContext[*tmplNode]) error {
 	case lexparse.TokenTypeEOF:
 		return fmt.Errorf("%w: expected %q", io.ErrUnexpectedEOF, tokenBlockStart)
 	default:
-		return lexTokenErr(errIdentifier, token)
+		return lexparse.NewTokenError(token, errIdentifier)
 	}
 
 	// Validate the command token.
@@ -446,8 +424,8 @@ func parseBlockStart(ctx *lexparse.ParserContext[*tmplNode]) error {
 	case lexparse.TokenTypeEOF:
 		return fmt.Errorf("%w: expected %q", io.ErrUnexpectedEOF, tokenBlockStart)
 	default:
-		return fmt.Errorf("%w: expected %q, %q, or %q",
-			lexTokenErr(errIdentifier, token), tokenIf, tokenElse, tokenEndif)
+		return lexparse.NewTokenError(token,
+			fmt.Errorf("%w: expected %q, %q, or %q", errIdentifier, tokenIf, tokenElse, tokenEndif))
 	}
 
 	// Handle the block command.
@@ -457,8 +435,8 @@ func parseBlockStart(ctx *lexparse.ParserContext[*tmplNode]) error {
 	case tokenElse, tokenEndif:
 		// NOTE: parseElse, parseEndif should already be on the stack.
 	default:
-		return lexTokenErr(
-			fmt.Errorf("%w: expected %q, %q, or %q", errIdentifier, tokenIf, tokenElse, tokenEndif), token)
+		return lexparse.NewTokenError(token,
+			fmt.Errorf("%w: expected %q, %q, or %q", errIdentifier, tokenIf, tokenElse, tokenEndif))
 	}
 
 	return nil
@@ -466,14 +444,9 @@ func parseBlockStart(ctx *lexparse.ParserContext[*tmplNode]) error {
 
 // parseBlockEnd handles the end of a template block '%}'.
 func parseBlockEnd(ctx *lexparse.ParserContext[*tmplNode]) error {
-	switch token := ctx.Next(); token.Type {
-	case lexTypeBlockEnd:
-		return nil
-	case lexparse.TokenTypeEOF:
-		return fmt.Errorf("%w: expected %q", io.ErrUnexpectedEOF, tokenBlockEnd)
-	default:
-		return lexTokenErr(fmt.Errorf("%w: expected %q", errIdentifier, tokenBlockEnd), token)
-	}
+	_, err := ctx.Expect(lexTypeBlockEnd, tokenBlockEnd)
+
+	return err //nolint:wrapcheck // Expect returns a *lexparse.Error.
 }
 
 // Execute renders the template with the given data.
@@ -0,0 +1,154 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"slices"
+)
+
+// The methods in this file modify a tree while keeping the Parent and
+// Children fields consistent. A node that is added to a tree is first
+// detached from its current parent. Start and End positions are not updated.
+
+// AppendChild adds child as the last child of n. AppendChild panics if child is
+// n or one of its ancestors.
+func (n *Node[V]) AppendChild(child *Node[V]) {
+	n.checkInsert(child)
+	child.Detach()
+
+	child.Parent = n
+	n.Children = append(n.Children, child)
+}
+
+// InsertBefore inserts sibling into the children of n's parent immediately
+// before n. InsertBefore panics if n has no parent or if sibling is n or one of
+// its ancestors.
+func (n *Node[V]) InsertBefore(sibling *Node[V]) {
+	n.insertSibling(sibling, 0)
+}
+
+// InsertAfter inserts sibling into the children of n's parent immediately after
+// n. InsertAfter panics if n has no parent or if sibling is n or one of its
+// ancestors.
+func (n *Node[V]) InsertAfter(sibling *Node[V]) {
+	n.insertSibling(sibling, 1)
+}
+
+func (n *Node[V]) insertSibling(sibling *Node[V], offset int) {
+	parent := n.Parent
+	if parent == nil {
+		panic("lexparse: node has no parent")
+	}
+
+	if sibling == n {
+		panic("lexparse: cannot insert a node as its own sibling")
+	}
+
+	parent.checkInsert(sibling)
+	sibling.Detach()
+
+	i := slices.Index(parent.Children, n)
+	sibling.Parent = parent
+	parent.Children = slices.Insert(parent.Children, i+offset, sibling)
+}
+
+// Remove removes child from the children of n. It returns false if child is
+// not a child of n.
+func (n *Node[V]) Remove(child *Node[V]) bool {
+	if child == nil || child.Parent != n {
+		return false
+	}
+
+	i := slices.Index(n.Children, child)
+	if i < 0 {
+		return false
+	}
+
+	n.Children = slices.Delete(n.Children, i, i+1)
+	child.Parent = nil
+
+	return true
+}
+
+// Detach removes n from the children of its parent. n becomes the root of its
+// own tree. Detach does nothing if n has no parent.
+func (n *Node[V]) Detach() {
+	if n.Parent != nil {
+		_ = n.Parent.Remove(n)
+	}
+}
+
+// ReplaceWith replaces n in the children of its parent with other and detaches
+// n. ReplaceWith does nothing if n has no parent. It panics if other is an
+// ancestor of n.
+func (n *Node[V]) ReplaceWith(other *Node[V]) {
+	parent := n.Parent
+	if parent == nil || other == n {
+		return
+	}
+
+	parent.checkInsert(other)
+	other.Detach()
+
+	i := slices.Index(parent.Children, n)
+	parent.Children[i] = other
+	other.Parent = parent
+	n.Parent = nil
+}
+
+// Clone returns a deep copy of the tree rooted at n. The copy has no parent.
+func (n *Node[V]) Clone() *Node[V] {
+	return cloneTree(n, nil)
+}
+
+// checkInsert panics if adding child to n would create a cycle.
+func (n *Node[V]) checkInsert(child *Node[V]) {
+	for p := n; p != nil; p = p.Parent {
+		if p == child {
+			panic("lexparse: cannot insert a node into its own subtree")
+		}
+	}
+}
+
+// Rewrite transforms the tree rooted at root from the bottom up. f is called
+// for each node after its children have been rewritten. If f returns a
+// different node, it replaces the node in the tree. If f returns nil, the node
+// is removed. Rewrite returns the new root of the tree, which is nil if the
+// root node was removed.
+func Rewrite[V comparable](root *Node[V], f func(*Node[V]) *Node[V]) *Node[V] {
+	if root == nil {
+		return nil
+	}
+
+	// Iterate over a copy as f may modify the children.
+	for _, child := range slices.Clone(root.Children) {
+		_ = Rewrite(child, f)
+	}
+
+	result := f(root)
+
+	switch {
+	case result == nil:
+		root.Detach()
+	case result != root:
+		if root.Parent != nil {
+			root.ReplaceWith(result)
+		} else {
+			result.Detach()
+		}
+	}
+
+	return result
+}
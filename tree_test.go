@@ -0,0 +1,298 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// checkParents reports an error for any node in the tree rooted at root whose
+// Parent field does not match its position in the tree.
+func checkParents[V comparable](t *testing.T, root *Node[V]) {
+	t.Helper()
+
+	if root.Parent != nil && !slices.Contains(root.Parent.Children, root) {
+		t.Errorf("node %v is not a child of its parent", root.Value)
+	}
+
+	for n := range root.All() {
+		for _, child := range n.Children {
+			if child.Parent != n {
+				t.Errorf("node %v: want parent %v, got %v", child.Value, n.Value, child.Parent)
+			}
+		}
+	}
+}
+
+// find returns the first node in the tree rooted at root with value v.
+func find(root *Node[string], v string) *Node[string] {
+	for n := range root.All() {
+		if n.Value == v {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// treeString formats the tree rooted at n compactly, e.g. a(b(d e) c(f)).
+func treeString(n *Node[string]) string {
+	if len(n.Children) == 0 {
+		return n.Value
+	}
+
+	children := make([]string, 0, len(n.Children))
+	for _, child := range n.Children {
+		children = append(children, treeString(child))
+	}
+
+	return n.Value + "(" + strings.Join(children, " ") + ")"
+}
+
+func TestNode_mutation(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		f    func(root *Node[string])
+		want string
+	}{
+		"AppendChild": {
+			f: func(root *Node[string]) {
+				root.AppendChild(&Node[string]{Value: "g"})
+			},
+			want: "a(b(d e) c(f) g)",
+		},
+		"AppendChild move": {
+			f: func(root *Node[string]) {
+				find(root, "c").AppendChild(find(root, "d"))
+			},
+			want: "a(b(e) c(f d))",
+		},
+		"InsertBefore": {
+			f: func(root *Node[string]) {
+				find(root, "e").InsertBefore(&Node[string]{Value: "g"})
+			},
+			want: "a(b(d g e) c(f))",
+		},
+		"InsertBefore first": {
+			f: func(root *Node[string]) {
+				find(root, "b").InsertBefore(find(root, "f"))
+			},
+			want: "a(f b(d e) c)",
+		},
+		"InsertAfter": {
+			f: func(root *Node[string]) {
+				find(root, "e").InsertAfter(&Node[string]{Value: "g"})
+			},
+			want: "a(b(d e g) c(f))",
+		},
+		"InsertAfter sibling": {
+			f: func(root *Node[string]) {
+				find(root, "d").InsertAfter(find(root, "e"))
+				find(root, "e").InsertAfter(find(root, "d"))
+			},
+			want: "a(b(e d) c(f))",
+		},
+		"Remove": {
+			f: func(root *Node[string]) {
+				if !root.Remove(find(root, "b")) {
+					t.Errorf("Remove: want true, got false")
+				}
+
+				if root.Remove(find(root, "f")) {
+					t.Errorf("Remove: want false, got true")
+				}
+			},
+			want: "a(c(f))",
+		},
+		"Detach": {
+			f: func(root *Node[string]) {
+				find(root, "c").Detach()
+				root.Detach()
+			},
+			want: "a(b(d e))",
+		},
+		"ReplaceWith": {
+			f: func(root *Node[string]) {
+				find(root, "b").ReplaceWith(&Node[string]{Value: "g"})
+			},
+			want: "a(g c(f))",
+		},
+		"ReplaceWith child": {
+			f: func(root *Node[string]) {
+				find(root, "c").ReplaceWith(find(root, "f"))
+			},
+			want: "a(b(d e) f)",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root := newTestTree()
+			tc.f(root)
+
+			if diff := cmp.Diff(tc.want, treeString(root)); diff != "" {
+				t.Errorf("tree (-want +got):\n%s", diff)
+			}
+
+			checkParents(t, root)
+		})
+	}
+}
+
+func TestNode_mutation_panics(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]func(root *Node[string]){
+		"AppendChild self": func(root *Node[string]) {
+			root.AppendChild(root)
+		},
+		"AppendChild ancestor": func(root *Node[string]) {
+			find(root, "d").AppendChild(find(root, "b"))
+		},
+		"InsertBefore root": func(root *Node[string]) {
+			root.InsertBefore(&Node[string]{Value: "g"})
+		},
+		"InsertBefore self": func(root *Node[string]) {
+			d := find(root, "d")
+			d.InsertBefore(d)
+		},
+		"InsertAfter self": func(root *Node[string]) {
+			d := find(root, "d")
+			d.InsertAfter(d)
+		},
+		"InsertAfter ancestor": func(root *Node[string]) {
+			find(root, "d").InsertAfter(find(root, "b"))
+		},
+		"ReplaceWith ancestor": func(root *Node[string]) {
+			find(root, "d").ReplaceWith(root)
+		},
+	}
+
+	for name, f := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+
+			f(newTestTree())
+		})
+	}
+}
+
+func TestNode_Clone(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+	b := find(root, "b")
+
+	c := b.Clone()
+
+	if c.Parent != nil {
+		t.Errorf("Parent: want nil, got %v", c.Parent)
+	}
+
+	if diff := cmp.Diff(treeString(b), treeString(c)); diff != "" {
+		t.Errorf("Clone (-want +got):\n%s", diff)
+	}
+
+	checkParents(t, c)
+
+	// Modifying the clone does not modify the original.
+	c.Children[0].Value = "x"
+	c.AppendChild(&Node[string]{Value: "y"})
+
+	if diff := cmp.Diff("b(d e)", treeString(b)); diff != "" {
+		t.Errorf("original (-want +got):\n%s", diff)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+
+	var visited []string
+
+	newRoot := Rewrite(root, func(n *Node[string]) *Node[string] {
+		visited = append(visited, n.Value)
+
+		switch n.Value {
+		case "d":
+			// Remove d.
+			return nil
+		case "e":
+			return &Node[string]{Value: "E"}
+		case "c":
+			// Replace c with its only child.
+			return n.Children[0]
+		default:
+			return n
+		}
+	})
+
+	if diff := cmp.Diff([]string{"d", "e", "b", "f", "c", "a"}, visited); diff != "" {
+		t.Errorf("visited (-want +got):\n%s", diff)
+	}
+
+	if newRoot != root {
+		t.Errorf("Rewrite: want %v, got %v", root, newRoot)
+	}
+
+	if diff := cmp.Diff("a(b(E) f)", treeString(newRoot)); diff != "" {
+		t.Errorf("Rewrite (-want +got):\n%s", diff)
+	}
+
+	checkParents(t, newRoot)
+}
+
+func TestRewrite_root(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+
+	// Replace the root with its first child.
+	newRoot := Rewrite(root, func(n *Node[string]) *Node[string] {
+		if n == root {
+			return n.Children[0]
+		}
+
+		return n
+	})
+
+	if diff := cmp.Diff("b(d e)", treeString(newRoot)); diff != "" {
+		t.Errorf("Rewrite (-want +got):\n%s", diff)
+	}
+
+	if newRoot.Parent != nil {
+		t.Errorf("Parent: want nil, got %v", newRoot.Parent)
+	}
+
+	checkParents(t, newRoot)
+
+	if got := Rewrite(root, func(*Node[string]) *Node[string] { return nil }); got != nil {
+		t.Errorf("Rewrite: want nil, got %v", got)
+	}
+}
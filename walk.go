@@ -0,0 +1,125 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"iter"
+)
+
+// Visitor visits the nodes of a tree traversed by [Walk].
+type Visitor[V comparable] interface {
+	// Enter is called when a node is visited before its children. If Enter
+	// returns false the node's children are skipped.
+	Enter(n *Node[V]) bool
+
+	// Leave is called after the node's children have been visited or
+	// skipped.
+	Leave(n *Node[V])
+}
+
+// Walk traverses the tree rooted at n in depth-first order. v.Enter is called
+// for each node before its children and v.Leave is called after them. The
+// children of a node are not visited if v.Enter returns false.
+func Walk[V comparable](n *Node[V], v Visitor[V]) {
+	if n == nil {
+		return
+	}
+
+	if v.Enter(n) {
+		for _, child := range n.Children {
+			Walk(child, v)
+		}
+	}
+
+	v.Leave(n)
+}
+
+// inspector is a [Visitor] that calls a function when entering a node.
+type inspector[V comparable] func(*Node[V]) bool
+
+func (f inspector[V]) Enter(n *Node[V]) bool {
+	return f(n)
+}
+
+func (f inspector[V]) Leave(*Node[V]) {}
+
+// Inspect traverses the tree rooted at n in depth-first order calling f for
+// each node before its children. The children of a node are not visited if f
+// returns false.
+func Inspect[V comparable](n *Node[V], f func(*Node[V]) bool) {
+	Walk[V](n, inspector[V](f))
+}
+
+// All returns an iterator over n and its descendants in depth-first order.
+func (n *Node[V]) All() iter.Seq[*Node[V]] {
+	return func(yield func(*Node[V]) bool) {
+		_ = n.all(yield)
+	}
+}
+
+// Descendants returns an iterator over the descendants of n in depth-first
+// order. n is not included.
+func (n *Node[V]) Descendants() iter.Seq[*Node[V]] {
+	return func(yield func(*Node[V]) bool) {
+		for _, child := range n.Children {
+			if !child.all(yield) {
+				return
+			}
+		}
+	}
+}
+
+// all yields n and its descendants and returns false if yield returned false.
+func (n *Node[V]) all(yield func(*Node[V]) bool) bool {
+	if !yield(n) {
+		return false
+	}
+
+	for _, child := range n.Children {
+		if !child.all(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Ancestors returns an iterator over the ancestors of n starting with its
+// parent and ending with the root node.
+func (n *Node[V]) Ancestors() iter.Seq[*Node[V]] {
+	return func(yield func(*Node[V]) bool) {
+		for p := n.Parent; p != nil; p = p.Parent {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// Siblings returns an iterator over the other children of n's parent in
+// order. n is not included.
+func (n *Node[V]) Siblings() iter.Seq[*Node[V]] {
+	return func(yield func(*Node[V]) bool) {
+		if n.Parent == nil {
+			return
+		}
+
+		for _, sibling := range n.Parent.Children {
+			if sibling != n && !yield(sibling) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lexparse
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestTree returns the following tree.
+//
+//	a
+//	├── b
+//	│   ├── d
+//	│   └── e
+//	└── c
+//	    └── f
+func newTestTree() *Node[string] {
+	return addParent(&Node[string]{
+		Value: "a",
+		Children: []*Node[string]{
+			{
+				Value: "b",
+				Children: []*Node[string]{
+					{Value: "d"},
+					{Value: "e"},
+				},
+			},
+			{
+				Value: "c",
+				Children: []*Node[string]{
+					{Value: "f"},
+				},
+			},
+		},
+	})
+}
+
+// values returns the values of the nodes in seq.
+func values[V comparable](seq iter.Seq[*Node[V]]) []V {
+	var vals []V
+	for n := range seq {
+		vals = append(vals, n.Value)
+	}
+
+	return vals
+}
+
+// testVisitor records the order nodes are visited.
+type testVisitor struct {
+	visits []string
+	skip   string
+}
+
+func (v *testVisitor) Enter(n *Node[string]) bool {
+	v.visits = append(v.visits, "enter "+n.Value)
+	return n.Value != v.skip
+}
+
+func (v *testVisitor) Leave(n *Node[string]) {
+	v.visits = append(v.visits, "leave "+n.Value)
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	v := &testVisitor{skip: "b"}
+	Walk(newTestTree(), v)
+
+	want := []string{
+		"enter a",
+		"enter b",
+		"leave b",
+		"enter c",
+		"enter f",
+		"leave f",
+		"leave c",
+		"leave a",
+	}
+
+	if diff := cmp.Diff(want, v.visits); diff != "" {
+		t.Errorf("Walk (-want +got):\n%s", diff)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+
+	Inspect(newTestTree(), func(n *Node[string]) bool {
+		got = append(got, n.Value)
+		return n.Value != "c"
+	})
+
+	if diff := cmp.Diff([]string{"a", "b", "d", "e", "c"}, got); diff != "" {
+		t.Errorf("Inspect (-want +got):\n%s", diff)
+	}
+}
+
+func TestNode_All(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+
+	if diff := cmp.Diff([]string{"a", "b", "d", "e", "c", "f"}, values(root.All())); diff != "" {
+		t.Errorf("All (-want +got):\n%s", diff)
+	}
+
+	// Stop early.
+	var got []string
+
+	for n := range root.All() {
+		if n.Value == "e" {
+			break
+		}
+
+		got = append(got, n.Value)
+	}
+
+	if diff := cmp.Diff([]string{"a", "b", "d"}, got); diff != "" {
+		t.Errorf("All (-want +got):\n%s", diff)
+	}
+}
+
+func TestNode_Descendants(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+
+	if diff := cmp.Diff([]string{"b", "d", "e", "c", "f"}, values(root.Descendants())); diff != "" {
+		t.Errorf("Descendants (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string(nil), values(root.Children[1].Children[0].Descendants())); diff != "" {
+		t.Errorf("Descendants (-want +got):\n%s", diff)
+	}
+}
+
+func TestNode_Ancestors(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+	f := root.Children[1].Children[0]
+
+	if diff := cmp.Diff([]string{"c", "a"}, values(f.Ancestors())); diff != "" {
+		t.Errorf("Ancestors (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string(nil), values(root.Ancestors())); diff != "" {
+		t.Errorf("Ancestors (-want +got):\n%s", diff)
+	}
+}
+
+func TestNode_Siblings(t *testing.T) {
+	t.Parallel()
+
+	root := newTestTree()
+	b := root.Children[0]
+
+	if diff := cmp.Diff([]string{"c"}, values(b.Siblings())); diff != "" {
+		t.Errorf("Siblings (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"d"}, values(b.Children[1].Siblings())); diff != "" {
+		t.Errorf("Siblings (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string(nil), values(root.Siblings())); diff != "" {
+		t.Errorf("Siblings (-want +got):\n%s", diff)
+	}
+}